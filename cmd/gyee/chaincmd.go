@@ -19,3 +19,169 @@
  */
 
 package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/urfave/cli"
+	"github.com/yeeco/gyee/config"
+	"github.com/yeeco/gyee/core"
+	"github.com/yeeco/gyee/persistent"
+	"github.com/yeeco/gyee/utils/logging"
+)
+
+var (
+	chainCommand = cli.Command{
+		Name:        "chain",
+		Usage:       "Manage blockchain data",
+		Category:    "CHAIN COMMANDS",
+		Description: "Manage local blockchain data, with node stopped",
+
+		Subcommands: []cli.Command{
+			{
+				Name:        "rewind",
+				Usage:       "Rewind chain head to block of number",
+				ArgsUsage:   "<number>",
+				Description: "Remove blocks above number from local chain, to recover from bad blocks",
+				Action:      config.MergeFlags(chainRewind),
+			},
+			{
+				Name:        "export",
+				Usage:       "Export blocks to file",
+				ArgsUsage:   "<file> [from] [to]",
+				Description: "Export blocks from..to of local chain, whole chain by default. File is gzip compressed if named *.gz",
+				Action:      config.MergeFlags(chainExport),
+			},
+			{
+				Name:        "import",
+				Usage:       "Import blocks from file",
+				ArgsUsage:   "<file>",
+				Description: "Import blocks exported by chain export, verified as blocks synced from peers",
+				Action:      config.MergeFlags(chainImport),
+			},
+		},
+	}
+)
+
+func chainRewind(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 {
+		logging.Logger.Fatal("No block number specified")
+	}
+	number, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		logging.Logger.Fatalf("block number parse failed:%s", err)
+	}
+
+	chain, storage := openChain(ctx)
+	defer closeChain(chain, storage)
+
+	head := chain.CurrentBlockHeight()
+	if err := chain.SetHead(number); err != nil {
+		return err
+	}
+	fmt.Printf("Chain rewound from %d to %d\n", head, number)
+	return nil
+}
+
+func chainExport(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) == 0 {
+		logging.Logger.Fatal("No export file specified")
+	}
+	chain, storage := openChain(ctx)
+	defer closeChain(chain, storage)
+
+	from, to := uint64(0), chain.CurrentBlockHeight()
+	var err error
+	if len(args) > 1 {
+		if from, err = strconv.ParseUint(args[1], 10, 64); err != nil {
+			return err
+		}
+	}
+	if len(args) > 2 {
+		if to, err = strconv.ParseUint(args[2], 10, 64); err != nil {
+			return err
+		}
+	}
+
+	file, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var (
+		w  io.Writer = file
+		gw *gzip.Writer
+	)
+	if strings.HasSuffix(args[0], ".gz") {
+		gw = gzip.NewWriter(file)
+		w = gw
+	}
+	count, err := chain.ExportChain(w, from, to)
+	if err != nil {
+		return err
+	}
+	if gw != nil {
+		// flush compressed data
+		if err := gw.Close(); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Exported %d blocks to %s\n", count, args[0])
+	return nil
+}
+
+func chainImport(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 {
+		logging.Logger.Fatal("No import file specified")
+	}
+	name := ctx.Args().First()
+	file, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	var r io.Reader = file
+	if strings.HasSuffix(name, ".gz") {
+		gr, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		r = gr
+	}
+
+	chain, storage := openChain(ctx)
+	defer closeChain(chain, storage)
+
+	count, err := chain.ImportChain(r)
+	fmt.Printf("Imported %d blocks, chain height %d\n", count, chain.CurrentBlockHeight())
+	return err
+}
+
+// open chain db of node for offline operations
+func openChain(ctx *cli.Context) (*core.BlockChain, persistent.Storage) {
+	conf := config.GetConfig(ctx)
+	storage, err := core.OpenChainStorage(conf)
+	if err != nil {
+		logging.Logger.Fatalf("chain db open failed:%s", err)
+	}
+	chain, err := core.NewBlockChainWithConfig(conf, storage, nil)
+	if err != nil {
+		_ = storage.Close()
+		logging.Logger.Fatalf("chain load failed:%s", err)
+	}
+	return chain, storage
+}
+
+func closeChain(chain *core.BlockChain, storage persistent.Storage) {
+	chain.Stop()
+	if err := storage.Close(); err != nil {
+		logging.Logger.Error("chain db close failed:", err)
+	}
+}
@@ -21,17 +21,15 @@
 package main
 
 import (
-	"net/rpc"
+	"context"
 
 	"github.com/urfave/cli"
 	"github.com/yeeco/gyee/config"
+	"github.com/yeeco/gyee/rpc"
+	"github.com/yeeco/gyee/rpc/pb"
 	"github.com/yeeco/gyee/utils/logging"
 )
 
-type Args struct {
-	S string
-}
-
 var (
 	ipcCommand = cli.Command{
 		Name:      "ipc",
@@ -44,13 +42,16 @@ var (
 		Description: "",
 		Action: func(ctx *cli.Context) error {
 			conf := config.GetConfig(ctx)
-			client, err := rpc.Dial("unix", conf.IPCEndpoint())
-			var reply string
+			conn, err := rpc.DialIPC(conf.IPCEndpoint())
+			if err != nil {
+				return err
+			}
+			defer conn.Close()
 
-			args := Args{S: "test"}
-			err = client.Call("JSService.Hello", args, &reply)
+			reply, err := rpcpb.NewApiServiceClient(conn).NodeInfo(context.Background(), &rpcpb.NonParamsRequest{})
 			if err != nil {
 				logging.Logger.Info(err)
+				return err
 			}
 			logging.Logger.Info(reply)
 			return nil
@@ -59,7 +59,7 @@ func newBridge( /*config *nebletpb.Config*/ host string, prompter UserPrompter,
 	//		bridge.host = "http://" + bridge.host
 	//	}
 	//} else {
-	bridge.host = "http://localhost:8685"
+	bridge.host = "http://localhost:7354" // default http_listen
 	//}
 	return bridge
 }
@@ -0,0 +1,146 @@
+/*
+ *  Copyright (C) 2017 gyee authors
+ *
+ *  This file is part of the gyee library.
+ *
+ *  The gyee library is free software: you can redistribute it and/or modify
+ *  it under the terms of the GNU General Public License as published by
+ *  the Free Software Foundation, either version 3 of the License, or
+ *  (at your option) any later version.
+ *
+ *  The gyee library is distributed in the hope that it will be useful,
+ *  but WITHOUT ANY WARRANTY; without even the implied warranty of
+ *  MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ *  GNU General Public License for more details.
+ *
+ *  You should have received a copy of the GNU General Public License
+ *  along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/urfave/cli"
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/config"
+	"github.com/yeeco/gyee/core"
+	"github.com/yeeco/gyee/persistent"
+	"github.com/yeeco/gyee/utils/logging"
+)
+
+var (
+	dbCommand = cli.Command{
+		Name:        "db",
+		Usage:       "Inspect and maintain chain db",
+		Category:    "CHAIN COMMANDS",
+		Description: "Low level operations on chain db, with node stopped",
+
+		Subcommands: []cli.Command{
+			{
+				Name:        "stats",
+				Usage:       "Show key counts and sizes by prefix",
+				ArgsUsage:   " ",
+				Description: "Walk all keys of chain db, reporting key count and bytes of each key prefix",
+				Action:      config.MergeFlags(dbStats),
+			},
+			{
+				Name:        "compact",
+				Usage:       "Compact chain db",
+				ArgsUsage:   " ",
+				Description: "Compact whole key range of leveldb chain db, reclaiming space of deleted data",
+				Action:      config.MergeFlags(dbCompact),
+			},
+			{
+				Name:        "get",
+				Usage:       "Print raw value of a key",
+				ArgsUsage:   "<prefix> <key>",
+				Description: "Print value of prefix + key in hex. Prefix is a raw key prefix (e.g. blkH-) or name shown by db stats, key is hex",
+				Action:      config.MergeFlags(dbGet),
+			},
+		},
+	}
+)
+
+// open chain db of node for offline operations
+func openChainDB(ctx *cli.Context) persistent.Storage {
+	storage, err := core.OpenChainStorage(config.GetConfig(ctx))
+	if err != nil {
+		logging.Logger.Fatalf("chain db open failed:%s", err)
+	}
+	return storage
+}
+
+func closeChainDB(storage persistent.Storage) {
+	if err := storage.Close(); err != nil {
+		logging.Logger.Error("chain db close failed:", err)
+	}
+}
+
+func dbStats(ctx *cli.Context) error {
+	storage := openChainDB(ctx)
+	defer closeChainDB(storage)
+
+	stats, err := core.InspectStorage(storage)
+	if err != nil {
+		return err
+	}
+	var count uint64
+	var size common.StorageSize
+	fmt.Printf("%-14s %-8s %12s %14s %14s\n", "NAME", "PREFIX", "KEYS", "KEY SIZE", "VALUE SIZE")
+	for _, s := range stats {
+		fmt.Printf("%-14s %-8s %12d %14s %14s\n", s.Name, s.Prefix, s.Count, s.KeySize, s.ValueSize)
+		count += s.Count
+		size += s.KeySize + s.ValueSize
+	}
+	fmt.Printf("Total %d keys, %s\n", count, size)
+	return nil
+}
+
+func dbCompact(ctx *cli.Context) error {
+	storage := openChainDB(ctx)
+	defer closeChainDB(storage)
+
+	ldb, ok := storage.(*persistent.LevelStorage)
+	if !ok {
+		return fmt.Errorf("compaction not supported by chain db engine")
+	}
+	start := time.Now()
+	fmt.Println("Compacting chain db...")
+	if err := ldb.Compact(); err != nil {
+		return err
+	}
+	fmt.Printf("Compaction done in %s\n", time.Since(start))
+	return nil
+}
+
+func dbGet(ctx *cli.Context) error {
+	args := ctx.Args()
+	if len(args) < 2 {
+		logging.Logger.Fatal("No prefix and key specified")
+	}
+	key, err := hex.DecodeString(trimHexPrefix(args[1]))
+	if err != nil {
+		logging.Logger.Fatalf("key parse failed:%s", err)
+	}
+	storage := openChainDB(ctx)
+	defer closeChainDB(storage)
+
+	value, err := storage.Get(append([]byte(core.DBPrefix(args[0])), key...))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%x\n", value)
+	return nil
+}
+
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+	return s
+}
@@ -60,6 +60,8 @@ func init() {
 		consoleCommand,
 		configCommand,
 		accountCommand,
+		chainCommand,
+		dbCommand,
 		licenseCommand,
 		versionCommand,
 	}
@@ -86,19 +86,21 @@ func NewAddressFromPublicKey(pubkey []byte) (*Address, error) {
 }
 
 func NewAddressFromCommonAddress(addr common.Address) *Address {
-	buffer := make([]byte, AddressLength)
-	buffer[AddressTypeIndex] = byte(AddressTypeAccount)
-	buffer[AddressNetworkIdIndex] = 0x05 //TODO：这个要从其他地方取
-	copy(buffer[AddressContentIndex:AddressChecksumIndex], addr[:])
-	cs := checkSum(buffer[:AddressChecksumIndex])
-	copy(buffer[AddressChecksumIndex:], cs)
-	return &Address{
-		Raw: buffer,
-	}
+	return newAddressFromContent(AddressTypeAccount, addr[:])
 }
 
-func NewContractAddressFromData() (*Address, error) {
-	return nil, nil
+// NewContractAddressFromCommonAddress renders content of a contract address with contract type
+func NewContractAddressFromCommonAddress(addr common.Address) *Address {
+	return newAddressFromContent(AddressTypeContract, addr[:])
+}
+
+// NewContractAddressFromData creates contract address with content derived from data,
+//   e.g. creator address and nonce
+func NewContractAddressFromData(data []byte) (*Address, error) {
+	if len(data) == 0 {
+		return nil, errors.New("empty contract address data")
+	}
+	return newAddressFromContent(AddressTypeContract, hash.Ripemd160(hash.Sha3256(data))), nil
 }
 
 // Bytes returns address bytes
@@ -156,6 +158,18 @@ func AddressParseFromBytes(b []byte) (*Address, error) {
 	return &Address{Raw: b}, nil
 }
 
+func newAddressFromContent(t AddressType, content []byte) *Address {
+	buffer := make([]byte, AddressLength)
+	buffer[AddressTypeIndex] = byte(t)
+	buffer[AddressNetworkIdIndex] = 0x05 //TODO：这个要从其他地方取
+	copy(buffer[AddressContentIndex:AddressChecksumIndex], content)
+	cs := checkSum(buffer[:AddressChecksumIndex])
+	copy(buffer[AddressChecksumIndex:], cs)
+	return &Address{
+		Raw: buffer,
+	}
+}
+
 func newAddressFromPublicKey(t AddressType, pubkey []byte) (*Address, error) {
 	buffer := make([]byte, AddressLength)
 	buffer[AddressTypeIndex] = byte(t)
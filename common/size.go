@@ -17,4 +17,22 @@
 
 package common
 
+import "fmt"
+
 type StorageSize float64
+
+// String formats size with binary unit
+func (s StorageSize) String() string {
+	switch {
+	case s > 1099511627776:
+		return fmt.Sprintf("%.2f TiB", s/1099511627776)
+	case s > 1073741824:
+		return fmt.Sprintf("%.2f GiB", s/1073741824)
+	case s > 1048576:
+		return fmt.Sprintf("%.2f MiB", s/1048576)
+	case s > 1024:
+		return fmt.Sprintf("%.2f KiB", s/1024)
+	default:
+		return fmt.Sprintf("%.2f B", s)
+	}
+}
@@ -31,6 +31,9 @@ var (
 	// value generated with sha3([]byte{0x80}), while 0x80 is rlp encoded byte for empty string
 	emptyRoot = common.HexToHash("bc2071a4de846f285702447f2589dd163678e0972a8a1b0d28b04ed5c094547f")
 
+	// EmptyRoot is emptyRoot for users of nested tries
+	EmptyRoot = emptyRoot
+
 	// emptyState is the known hash of an empty state trie entry.
 	emptyState = common.BytesToHash(hash.Sha3256(nil))
 )
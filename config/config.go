@@ -84,18 +84,27 @@ type RpcConfig struct {
 	IpcPath    string   `toml:"ipc_path"`
 	RpcListen  []string `toml:"rpc_listen"`
 	HttpListen []string `toml:"http_listen"`
+	HttpCors   []string `toml:"http_cors"`  // origins allowed for cross-origin http requests, "*" for any
+	HttpAdmin  bool     `toml:"http_admin"` // serve admin apis on http gateway, ipc only by default
 }
 
 //Genesis, ChainID, Keydir, Coinbase, gas...
 type ChainConfig struct {
-	ChainID  uint32 `toml:"chain_id"`
-	DataDir  string `toml:"data_dir"`
-	KeyDir   string `toml:"key_dir"`
-	Genesis  string `toml:"genesis"`
-	Mine     bool   `toml:"mine"`
-	Coinbase string `toml:"coinbase"`
-	PwdFile  string `toml:"pwdfile"`
-	Key      []byte // raw private key used in unit test
+	ChainID      uint32 `toml:"chain_id"`
+	DataDir      string `toml:"data_dir"`
+	KeyDir       string `toml:"key_dir"`
+	Genesis      string `toml:"genesis"`
+	Mine         bool   `toml:"mine"`
+	Coinbase     string `toml:"coinbase"`
+	PwdFile      string `toml:"pwdfile"`
+	MinTxFee     uint64 `toml:"min_tx_fee"`    // min fee for tx accepted by tx pool
+	AddressIndex bool   `toml:"address_index"` // index txs by sender / recipient address
+	StatePruning bool   `toml:"state_pruning"` // keep state of recent blocks only, all blocks by default
+	StateHistory uint64 `toml:"state_history"` // recent blocks with state kept while pruning
+	TrieCache    int    `toml:"trie_cache"`    // MB of trie nodes kept in memory
+	Checkpoint   string `toml:"checkpoint"`    // hex hash of trusted block to sync state from on empty chain
+	DBEngine     string `toml:"db_engine"`     // key-value engine of chain db, leveldb (default) or boltdb
+	Key          []byte // raw private key used in unit test
 }
 
 //cpu, mem, disk profile,
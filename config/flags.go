@@ -96,6 +96,8 @@ var (
 		RpcIpcPathFlag,
 		RpcListenFlag,
 		RpcHttpListenFlag,
+		RpcHttpCorsFlag,
+		RpcHttpAdminFlag,
 	}
 
 	RpcIpcPathFlag = cli.StringFlag{
@@ -113,6 +115,16 @@ var (
 		Usage: "http listen",
 	}
 
+	RpcHttpCorsFlag = cli.StringSliceFlag{
+		Name:  "http_cors",
+		Usage: "origins allowed for cross-origin http requests, * for any",
+	}
+
+	RpcHttpAdminFlag = cli.BoolFlag{
+		Name:  "http_admin",
+		Usage: "serve admin apis on http gateway",
+	}
+
 	//ChainConfig Flags
 	ChainFlags = []cli.Flag{
 		ChainIDFlag,
@@ -122,6 +134,13 @@ var (
 		ChainMineFlag,
 		ChainCoinbaseFlag,
 		ChainPwdFileFlag,
+		ChainMinTxFeeFlag,
+		ChainAddressIndexFlag,
+		ChainStatePruningFlag,
+		ChainStateHistoryFlag,
+		ChainTrieCacheFlag,
+		ChainCheckpointFlag,
+		ChainDBEngineFlag,
 	}
 
 	ChainIDFlag = cli.IntFlag{
@@ -159,6 +178,41 @@ var (
 		Usage: "pwdfile for coinbase keystore",
 	}
 
+	ChainMinTxFeeFlag = cli.Uint64Flag{
+		Name:  "mintxfee",
+		Usage: "min fee for tx accepted by tx pool",
+	}
+
+	ChainAddressIndexFlag = cli.BoolFlag{
+		Name:  "addrindex",
+		Usage: "index txs by sender / recipient address",
+	}
+
+	ChainStatePruningFlag = cli.BoolFlag{
+		Name:  "statepruning",
+		Usage: "keep state of recent blocks only, pruning state of old blocks",
+	}
+
+	ChainStateHistoryFlag = cli.Uint64Flag{
+		Name:  "statehistory",
+		Usage: "recent blocks with state kept while pruning",
+	}
+
+	ChainTrieCacheFlag = cli.IntFlag{
+		Name:  "triecache",
+		Usage: "MB of trie nodes kept in memory",
+	}
+
+	ChainCheckpointFlag = cli.StringFlag{
+		Name:  "checkpoint",
+		Usage: "hash of trusted block to sync state from, on empty chain",
+	}
+
+	ChainDBEngineFlag = cli.StringFlag{
+		Name:  "dbengine",
+		Usage: "key-value engine of chain db, leveldb or boltdb",
+	}
+
 	//MetricsConfig Flags
 	MetricsFlags = []cli.Flag{
 		MetricsEnableFlag,
@@ -237,6 +291,14 @@ func getRpcConfig(ctx *cli.Context, cfg *Config) {
 	if ctx.GlobalIsSet(FlagName(RpcHttpListenFlag.Name)) {
 		cfg.Rpc.HttpListen = ctx.GlobalStringSlice(FlagName(RpcHttpListenFlag.Name))
 	}
+
+	if ctx.GlobalIsSet(FlagName(RpcHttpCorsFlag.Name)) {
+		cfg.Rpc.HttpCors = ctx.GlobalStringSlice(FlagName(RpcHttpCorsFlag.Name))
+	}
+
+	if ctx.GlobalIsSet(FlagName(RpcHttpAdminFlag.Name)) {
+		cfg.Rpc.HttpAdmin = ctx.GlobalBool(FlagName(RpcHttpAdminFlag.Name))
+	}
 }
 
 func getChainConfig(ctx *cli.Context, cfg *Config) {
@@ -271,6 +333,34 @@ func getChainConfig(ctx *cli.Context, cfg *Config) {
 	if ctx.GlobalIsSet(FlagName(ChainPwdFileFlag.Name)) {
 		cfg.Chain.PwdFile = ctx.GlobalString(FlagName(ChainPwdFileFlag.Name))
 	}
+
+	if ctx.GlobalIsSet(FlagName(ChainMinTxFeeFlag.Name)) {
+		cfg.Chain.MinTxFee = ctx.GlobalUint64(FlagName(ChainMinTxFeeFlag.Name))
+	}
+
+	if ctx.GlobalIsSet(FlagName(ChainAddressIndexFlag.Name)) {
+		cfg.Chain.AddressIndex = ctx.GlobalBool(FlagName(ChainAddressIndexFlag.Name))
+	}
+
+	if ctx.GlobalIsSet(FlagName(ChainStatePruningFlag.Name)) {
+		cfg.Chain.StatePruning = ctx.GlobalBool(FlagName(ChainStatePruningFlag.Name))
+	}
+
+	if ctx.GlobalIsSet(FlagName(ChainStateHistoryFlag.Name)) {
+		cfg.Chain.StateHistory = ctx.GlobalUint64(FlagName(ChainStateHistoryFlag.Name))
+	}
+
+	if ctx.GlobalIsSet(FlagName(ChainTrieCacheFlag.Name)) {
+		cfg.Chain.TrieCache = ctx.GlobalInt(FlagName(ChainTrieCacheFlag.Name))
+	}
+
+	if ctx.GlobalIsSet(FlagName(ChainCheckpointFlag.Name)) {
+		cfg.Chain.Checkpoint = ctx.GlobalString(FlagName(ChainCheckpointFlag.Name))
+	}
+
+	if ctx.GlobalIsSet(FlagName(ChainDBEngineFlag.Name)) {
+		cfg.Chain.DBEngine = ctx.GlobalString(FlagName(ChainDBEngineFlag.Name))
+	}
 }
 
 func getMetricsConfig(ctx *cli.Context, cfg *Config) {
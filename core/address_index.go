@@ -0,0 +1,277 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/log"
+	"github.com/yeeco/gyee/persistent"
+)
+
+// Address tx index, optional index of txs by sender / recipient
+//   entries of an address are numbered in sealed order, appended on storeBlock
+//   entries of blocks replaced by a fork are truncated when the height is stored again,
+//   and entries of other blocks dropped from canonical chain on reorg,
+//   so entries of an address are all canonical, paged by seq directly
+
+// flags of an address tx entry
+const (
+	AddressTxSent     uint8 = 1 << iota // address is sender of tx
+	AddressTxReceived                   // address is recipient of tx
+)
+
+// encoded entry: number(8) index(4) flags(1) blockHash txHash
+const addressTxSize = 8 + 4 + 1 + 2*common.HashLength
+
+var (
+	ErrAddressIndexDisabled = errors.New("core.chain: address index disabled")
+)
+
+// AddressTx locates a tx involving an address in chain
+type AddressTx struct {
+	Number    uint64 // block number
+	Index     uint32 // tx index in block
+	Flags     uint8
+	BlockHash common.Hash
+	TxHash    common.Hash
+}
+
+func (e *AddressTx) Sent() bool     { return e.Flags&AddressTxSent != 0 }
+func (e *AddressTx) Received() bool { return e.Flags&AddressTxReceived != 0 }
+
+func (e *AddressTx) encode() []byte {
+	enc := make([]byte, addressTxSize)
+	binary.BigEndian.PutUint64(enc[0:8], e.Number)
+	binary.BigEndian.PutUint32(enc[8:12], e.Index)
+	enc[12] = e.Flags
+	copy(enc[13:13+common.HashLength], e.BlockHash[:])
+	copy(enc[13+common.HashLength:], e.TxHash[:])
+	return enc
+}
+
+func decodeAddressTx(enc []byte) *AddressTx {
+	if len(enc) != addressTxSize {
+		return nil
+	}
+	e := &AddressTx{
+		Number: binary.BigEndian.Uint64(enc[0:8]),
+		Index:  binary.BigEndian.Uint32(enc[8:12]),
+		Flags:  enc[12],
+	}
+	e.BlockHash.SetBytes(enc[13 : 13+common.HashLength])
+	e.TxHash.SetBytes(enc[13+common.HashLength:])
+	return e
+}
+
+// entries of txs in block, grouped by address
+//   addresses returned in order of first appearance
+func addressTxsOfBlock(b *Block) ([]common.Address, map[common.Address][]*AddressTx) {
+	var (
+		addrs   = make([]common.Address, 0)
+		entries = make(map[common.Address][]*AddressTx)
+		hash    = b.Hash()
+	)
+	add := func(addr common.Address, e AddressTx, flag uint8) {
+		list := entries[addr]
+		if n := len(list); n > 0 && list[n-1].Index == e.Index {
+			// tx to self
+			list[n-1].Flags |= flag
+			return
+		}
+		if list == nil {
+			addrs = append(addrs, addr)
+		}
+		e.Flags = flag
+		entries[addr] = append(list, &e)
+	}
+	for i, tx := range b.transactions {
+		e := AddressTx{
+			Number:    b.Number(),
+			Index:     uint32(i),
+			BlockHash: hash,
+			TxHash:    *tx.Hash(),
+		}
+		if tx.from == nil {
+			if err := tx.VerifySig(); err != nil {
+				log.Warn("address index: tx without sender", "hash", tx.Hash(), "err", err)
+			}
+		}
+		if tx.from != nil {
+			add(*tx.from, e, AddressTxSent)
+		}
+		if tx.to != nil {
+			add(*tx.to, e, AddressTxReceived)
+		}
+	}
+	return addrs, entries
+}
+
+// index txs of block in batch
+//   entries of the same or higher blocks are replaced
+func writeAddressIndex(getter persistent.Getter, batch persistent.Batch, b *Block) error {
+	addrs, entries := addressTxsOfBlock(b)
+	for _, addr := range addrs {
+		count := getAddressTxCount(getter, addr)
+		for count > 0 {
+			e := getAddressTx(getter, addr, count-1)
+			if e != nil && e.Number < b.Number() {
+				break
+			}
+			if err := batch.Del(keyAddressTx(addr, count-1)); err != nil {
+				return err
+			}
+			count--
+		}
+		for _, e := range entries[addr] {
+			putAddressTx(batch, addr, count, e)
+			count++
+		}
+		putAddressTxCount(batch, addr, count)
+	}
+	return nil
+}
+
+// remove index entries of blocks dropped from canonical chain in batch
+//   entries of dropped blocks are at tail, being the highest blocks indexed
+func deleteAddressIndex(getter persistent.Getter, batch persistent.Batch, blocks []*Block) error {
+	var (
+		dropped = make(map[common.Hash]struct{}, len(blocks))
+		addrs   = make(map[common.Address]struct{})
+	)
+	for _, b := range blocks {
+		dropped[b.Hash()] = struct{}{}
+		list, _ := addressTxsOfBlock(b)
+		for _, addr := range list {
+			addrs[addr] = struct{}{}
+		}
+	}
+	for addr := range addrs {
+		total := getAddressTxCount(getter, addr)
+		count := total
+		for count > 0 {
+			e := getAddressTx(getter, addr, count-1)
+			if e != nil {
+				if _, ok := dropped[e.BlockHash]; !ok {
+					break
+				}
+			}
+			if err := batch.Del(keyAddressTx(addr, count-1)); err != nil {
+				return err
+			}
+			count--
+		}
+		if count != total {
+			putAddressTxCount(batch, addr, count)
+		}
+	}
+	return nil
+}
+
+// switch address index on / off
+//   index rebuilt on enabling, if blocks were stored without indexing
+func (bc *BlockChain) setAddressIndex(enabled bool) error {
+	if !enabled {
+		bc.addrIndex = false
+		return bc.storage.Del(keyAddressIndexed())
+	}
+	indexed, err := bc.storage.Has(keyAddressIndexed())
+	if err != nil {
+		return err
+	}
+	if !indexed {
+		if err := bc.RebuildAddressIndex(); err != nil {
+			return err
+		}
+	}
+	bc.addrIndex = true
+	return nil
+}
+
+// RebuildAddressIndex indexes txs of all blocks in canonical chain
+func (bc *BlockChain) RebuildAddressIndex() error {
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	head := bc.LastBlock().Number()
+	log.Info("Rebuilding address index", "blocks", head+1)
+	for n := uint64(0); n <= head; n++ {
+		hash := getBlockNum2Hash(bc.storage, n)
+		if hash == common.EmptyHash {
+			// below block of state synced from peers
+			continue
+		}
+		b := new(Block)
+		if err := b.setProto(getHeader(bc.storage, hash), getBlockBody(bc.storage, hash)); err != nil {
+			return err
+		}
+		if len(b.transactions) == 0 {
+			continue
+		}
+		batch := bc.storage.NewBatch()
+		if err := writeAddressIndex(bc.storage, batch, b); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+	}
+	return bc.storage.Put(keyAddressIndexed(), []byte{1})
+}
+
+// GetAddressTxs returns txs involving addr in canonical chain, latest first
+//   the first offset txs are skipped, at most limit txs returned
+func (bc *BlockChain) GetAddressTxs(addr common.Address, offset, limit int) ([]*AddressTx, error) {
+	if !bc.addrIndex {
+		return nil, ErrAddressIndexDisabled
+	}
+	bc.chainmu.RLock()
+	defer bc.chainmu.RUnlock()
+
+	result := make([]*AddressTx, 0)
+	count := getAddressTxCount(bc.storage, addr)
+	if offset < 0 || uint64(offset) >= count || limit <= 0 {
+		return result, nil
+	}
+	// entries of page are seq [start, end), walked from start
+	end := count - uint64(offset)
+	start := uint64(0)
+	if end > uint64(limit) {
+		start = end - uint64(limit)
+	}
+	it := bc.storage.Iterator(keyAddressTxPrefix(addr), keyAddressTxSeq(start))
+	defer it.Release()
+	for seq := start; seq < end && it.Next(); seq++ {
+		e := decodeAddressTx(it.Value())
+		if e == nil || getBlockNum2Hash(bc.storage, e.Number) != e.BlockHash {
+			// block being dropped from canonical chain
+			continue
+		}
+		result = append(result, e)
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	// latest first
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result, nil
+}
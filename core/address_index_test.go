@@ -0,0 +1,167 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/persistent"
+)
+
+func checkAddressTxs(t *testing.T, chain *BlockChain, addr common.Address, offset, limit int, expected ...*AddressTx) {
+	got, err := chain.GetAddressTxs(addr, offset, limit)
+	if err != nil {
+		t.Fatalf("GetAddressTxs() %v", err)
+	}
+	if len(got) != len(expected) {
+		t.Fatalf("GetAddressTxs(%x, %d, %d) need %d txs, got %d",
+			addr, offset, limit, len(expected), len(got))
+	}
+	for i, e := range expected {
+		if *got[i] != *e {
+			t.Errorf("GetAddressTxs(%x, %d, %d)[%d] need %v got %v",
+				addr, offset, limit, i, e, got[i])
+		}
+	}
+}
+
+func addressTx(t *testing.T, b *Block, index int, flags uint8) *AddressTx {
+	txs := b.transactions
+	if txs == nil {
+		// block loaded from chain, decode txs from body
+		decoded := new(Block)
+		if err := decoded.setProto(b.pbHeader, b.body); err != nil {
+			t.Fatalf("setProto() %v", err)
+		}
+		txs = decoded.transactions
+	}
+	return &AddressTx{
+		Number:    b.Number(),
+		Index:     uint32(index),
+		Flags:     flags,
+		BlockHash: b.Hash(),
+		TxHash:    *txs[index].Hash(),
+	}
+}
+
+func TestAddressIndex(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	if _, err := chain.GetAddressTxs(tv.addrs[0], 0, 10); err != ErrAddressIndexDisabled {
+		t.Fatalf("query disabled index, got %v", err)
+	}
+	if err := chain.setAddressIndex(true); err != nil {
+		t.Fatalf("setAddressIndex() %v", err)
+	}
+
+	b1 := tv.nextBlock(t, chain, chain.LastBlock(), Transactions{
+		tv.newTx(t, 0, 1, 0, 10),
+		tv.newTx(t, 0, 2, 1, 10),
+	})
+	if err := chain.AddBlock(b1); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+	b2 := tv.nextBlock(t, chain, b1, Transactions{
+		tv.newTx(t, 1, 0, 0, 5),
+		tv.newTx(t, 0, 0, 2, 1), // to self
+	})
+	if err := chain.AddBlock(b2); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+
+	all := []*AddressTx{
+		addressTx(t, b2, 1, AddressTxSent|AddressTxReceived),
+		addressTx(t, b2, 0, AddressTxReceived),
+		addressTx(t, b1, 1, AddressTxSent),
+		addressTx(t, b1, 0, AddressTxSent),
+	}
+	checkAddressTxs(t, chain, tv.addrs[0], 0, 10, all...)
+	checkAddressTxs(t, chain, tv.addrs[0], 1, 2, all[1:3]...)
+	checkAddressTxs(t, chain, tv.addrs[0], 4, 10)
+	checkAddressTxs(t, chain, tv.addrs[1], 0, 10,
+		addressTx(t, b2, 0, AddressTxSent),
+		addressTx(t, b1, 0, AddressTxReceived))
+	checkAddressTxs(t, chain, tv.addrs[2], 0, 10,
+		addressTx(t, b1, 1, AddressTxReceived))
+}
+
+func TestAddressIndexReorg(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	if err := chain.setAddressIndex(true); err != nil {
+		t.Fatalf("setAddressIndex() %v", err)
+	}
+	tv.growChain(t, chain, 3)
+	b1 := chain.GetBlockByNumber(1)
+
+	// fork at height 2, tx of validator 2 only
+	fork := tv.nextBlock(t, chain, b1, Transactions{tv.newTx(t, 2, 1, 0, 1)})
+	if err := chain.Reorg(fork); err != nil {
+		t.Fatalf("Reorg() %v", err)
+	}
+	checkAddressTxs(t, chain, tv.addrs[0], 0, 10,
+		addressTx(t, b1, 0, AddressTxSent))
+	checkAddressTxs(t, chain, tv.addrs[1], 0, 10,
+		addressTx(t, fork, 0, AddressTxReceived),
+		addressTx(t, b1, 0, AddressTxReceived))
+	// entries of dropped blocks 2, 3 removed
+	if count := getAddressTxCount(chain.storage, tv.addrs[0]); count != 1 {
+		t.Errorf("index entries of sender, need 1 got %d", count)
+	}
+
+	tv.growChain(t, chain, 2)
+	b3, b4 := chain.GetBlockByNumber(3), chain.LastBlock()
+	checkAddressTxs(t, chain, tv.addrs[0], 0, 10,
+		addressTx(t, b4, 0, AddressTxSent),
+		addressTx(t, b3, 0, AddressTxSent),
+		addressTx(t, b1, 0, AddressTxSent))
+	checkAddressTxs(t, chain, tv.addrs[0], 1, 1,
+		addressTx(t, b3, 0, AddressTxSent))
+	checkAddressTxs(t, chain, tv.addrs[1], 1, 10,
+		addressTx(t, b3, 0, AddressTxReceived),
+		addressTx(t, fork, 0, AddressTxReceived),
+		addressTx(t, b1, 0, AddressTxReceived))
+}
+
+func TestAddressIndexRebuild(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	tv.growChain(t, chain, 3)
+
+	// enabling index on existing chain indexes all blocks
+	if err := chain.setAddressIndex(true); err != nil {
+		t.Fatalf("setAddressIndex() %v", err)
+	}
+	expected := make([]*AddressTx, 0, 4)
+	for n := uint64(3); n > 0; n-- {
+		expected = append(expected, addressTx(t, chain.GetBlockByNumber(n), 0, AddressTxSent))
+	}
+	checkAddressTxs(t, chain, tv.addrs[0], 0, 10, expected...)
+
+	// blocks stored while disabled are indexed on enabling again
+	if err := chain.setAddressIndex(false); err != nil {
+		t.Fatalf("setAddressIndex() %v", err)
+	}
+	tv.growChain(t, chain, 1)
+	if err := chain.setAddressIndex(true); err != nil {
+		t.Fatalf("setAddressIndex() %v", err)
+	}
+	expected = append([]*AddressTx{addressTx(t, chain.LastBlock(), 0, AddressTxSent)}, expected...)
+	checkAddressTxs(t, chain, tv.addrs[0], 0, 10, expected...)
+}
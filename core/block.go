@@ -41,7 +41,9 @@ import (
 var (
 	EmptyRootHash = DeriveHash(Transactions{})
 
-	ErrBlockBodyTxsMismatch = errors.New("block body txs mismatch")
+	ErrBlockBodyTxsMismatch  = errors.New("block body txs mismatch")
+	ErrBlockReceiptsMismatch = errors.New("block receipts root mismatch")
+	ErrBlockHeaderMissing    = errors.New("block header missing")
 )
 
 // Block Header of yee chain
@@ -107,7 +109,7 @@ type Block struct {
 	stateTrie     state.AccountTrie
 	consensusTrie state.ConsensusTrie
 	transactions  Transactions
-	// TODO: receipts
+	receipts      Receipts
 
 	// cache
 	hash       atomic.Value
@@ -143,6 +145,9 @@ func (b *Block) ReceiptsRoot() common.Hash  { return b.header.ReceiptsRoot }
 func (b *Block) Time() uint64  { return b.header.Time }
 func (b *Block) Extra() []byte { return b.header.Extra }
 
+func (b *Block) Header() *BlockHeader       { return CopyHeader(b.header) }
+func (b *Block) Transactions() Transactions { return b.transactions }
+
 func (b *Block) Hash() common.Hash {
 	if hash := b.hash.Load(); hash != nil {
 		return hash.(common.Hash)
@@ -172,6 +177,12 @@ func (b *Block) updateHeader() error {
 		return err
 	}
 	b.header.TxsRoot = DeriveHash(b.transactions)
+	// genesis block carries no receipts
+	if b.receipts != nil {
+		if b.header.ReceiptsRoot, err = b.receipts.hash(); err != nil {
+			return err
+		}
+	}
 	if b.pbHeader != nil {
 		log.Crit("update signed header")
 	}
@@ -265,6 +276,13 @@ func (b *Block) Signers() (map[common.Address]crypto.Signature, error) {
 }
 
 func (b *Block) prepareTrie(stateDB state.Database) error {
+	if err := b.prepareStateTrie(stateDB); err != nil {
+		return err
+	}
+	return b.prepareConsensusTrie(stateDB)
+}
+
+func (b *Block) prepareStateTrie(stateDB state.Database) error {
 	if b.stateTrie == nil {
 		stateTrie, err := state.NewAccountTrie(b.header.StateRoot, stateDB)
 		if err != nil {
@@ -272,6 +290,10 @@ func (b *Block) prepareTrie(stateDB state.Database) error {
 		}
 		b.stateTrie = stateTrie
 	}
+	return nil
+}
+
+func (b *Block) prepareConsensusTrie(stateDB state.Database) error {
 	if b.consensusTrie == nil {
 		consensusTrie, err := state.NewConsensusTrie(b.header.ConsensusRoot, stateDB)
 		if err != nil {
@@ -322,13 +344,23 @@ func (b *Block) setBytes(enc []byte) error {
 	if err := proto.Unmarshal(enc, pbBlock); err != nil {
 		return err
 	}
+	return b.setProto(pbBlock.Header, pbBlock.Body)
+}
+
+func (b *Block) setProto(pbHeader *corepb.SignedBlockHeader, body *corepb.BlockBody) error {
+	if pbHeader == nil {
+		return ErrBlockHeaderMissing
+	}
+	if body == nil {
+		body = new(corepb.BlockBody)
+	}
 	header := new(BlockHeader)
-	if err := rlp.DecodeBytes(pbBlock.Header.Header, header); err != nil {
+	if err := rlp.DecodeBytes(pbHeader.Header, header); err != nil {
 		return err
 	}
 	b.header = header
-	b.pbHeader = pbBlock.Header
-	b.body = pbBlock.Body
+	b.pbHeader = pbHeader
+	b.body = body
 	b.transactions = make(Transactions, 0, len(b.body.RawTransactions))
 	for _, raw := range b.body.RawTransactions {
 		tx := new(Transaction)
@@ -354,10 +386,13 @@ func (b *Block) Write(putter persistent.Putter) error {
 			return ErrBlockStateTrieMismatch
 		}
 	}
-	// add block header to storage
-	pbHeader, err := b.header.toSignedProto()
-	if err != nil {
-		return err
+	// add block header with signatures to storage
+	pbHeader := b.pbHeader
+	if pbHeader == nil {
+		var err error
+		if pbHeader, err = b.header.toSignedProto(); err != nil {
+			return err
+		}
 	}
 	hashHeader := putHeader(putter, pbHeader)
 	// add block body to storage
@@ -374,6 +409,10 @@ func (b *Block) Write(putter persistent.Putter) error {
 	if err := b.transactions.Write(putter); err != nil {
 		return err
 	}
+	// add tx receipts to storage, key "rcpt"+tx.hash
+	if err := b.receipts.Write(putter); err != nil {
+		return err
+	}
 
 	return nil
 }
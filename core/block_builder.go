@@ -18,62 +18,115 @@
 package core
 
 import (
+	"container/heap"
+	"sort"
+
 	"github.com/yeeco/gyee/common"
 	"github.com/yeeco/gyee/core/state"
 	"github.com/yeeco/gyee/log"
 )
 
+// organizeTxs orders txs for sealing in block
+//   txs of a sender go in nonce order, starting from account nonce
+//   among senders, next tx with higher fee goes first
+//   ties are broken by input order, so all validators get the same result
 func organizeTxs(state state.AccountTrie, txs Transactions) Transactions {
 	if len(txs) < 2 {
 		return txs
 	}
+
+	// group txs by sender, keep sender order of first appearance
 	var (
-		output   Transactions
-		nonceMap = make(map[common.Address]uint64)
+		senders  []common.Address
+		queueMap = make(map[common.Address][]*indexedTx)
 	)
-	for {
-		txCount := len(txs)
-		var nextRound Transactions
+	for i, tx := range txs {
+		if tx.from == nil {
+			// TODO: ignore for now
+			log.Warn("tx ignored due to nil from")
+			continue
+		}
+		from := *tx.from
+		if _, ok := queueMap[from]; !ok {
+			senders = append(senders, from)
+		}
+		queueMap[from] = append(queueMap[from], &indexedTx{tx: tx, index: i})
+	}
 
-		// sweep txs
-		for _, tx := range txs {
-			if tx.from == nil {
+	// per sender, keep consecutive nonces from account nonce
+	heads := make(txsByFee, 0, len(senders))
+	for _, from := range senders {
+		queue := queueMap[from]
+		sort.SliceStable(queue, func(i, j int) bool {
+			return queue[i].tx.nonce < queue[j].tx.nonce
+		})
+		var nonce uint64
+		if account := state.GetAccount(from, false); account != nil {
+			nonce = account.Nonce()
+		}
+		sealable := make([]*indexedTx, 0, len(queue))
+		for _, itx := range queue {
+			if itx.tx.nonce < nonce {
 				// TODO: ignore for now
-				log.Warn("tx ignored due to nil from")
+				log.Warn("tx nonce too low", "nonce", nonce, "tx", itx.tx)
 				continue
 			}
-			from := *tx.from
-			nonce, ok := nonceMap[from]
-			if !ok {
-				account := state.GetAccount(from, false)
-				if account != nil {
-					nonce = account.Nonce()
-				} else {
-					nonce = 0
-				}
-				nonceMap[from] = nonce
-			}
-			switch {
-			case tx.nonce == nonce:
-				output = append(output, tx)
-				nonceMap[from]++
-			case tx.nonce > nonce:
-				nextRound = append(nextRound, tx)
-			default:
-				// TODO: ignore for now
-				log.Warn("tx nonce too low", "nonce", nonce, "tx", tx)
+			if itx.tx.nonce > nonce {
+				log.Warn("engine output nonce not possible", "nonce", nonce, "tx", itx.tx)
+				break
 			}
+			sealable = append(sealable, itx)
+			nonce++
 		}
-
-		// check if we need another round
-		txs = nextRound
-		if len(txs) == 0 {
-			break
+		if len(sealable) > 0 {
+			heads = append(heads, sealable)
 		}
-		if txCount == len(txs) {
-			log.Warn("engine output nonce not possible", "remain", txs)
-			break
+	}
+
+	// merge sender queues by fee of next tx
+	output := make(Transactions, 0, len(txs))
+	heap.Init(&heads)
+	for heads.Len() > 0 {
+		queue := heads[0]
+		output = append(output, queue[0].tx)
+		if len(queue) > 1 {
+			heads[0] = queue[1:]
+			heap.Fix(&heads, 0)
+		} else {
+			heap.Pop(&heads)
 		}
 	}
 	return output
 }
+
+// tx with its position in input
+type indexedTx struct {
+	tx    *Transaction
+	index int
+}
+
+// heap of nonce ordered sender queues, by fee then input position of first tx
+type txsByFee [][]*indexedTx
+
+func (h txsByFee) Len() int { return len(h) }
+
+func (h txsByFee) Less(i, j int) bool {
+	if cmp := h[i][0].tx.Fee().Cmp(h[j][0].tx.Fee()); cmp != 0 {
+		return cmp > 0
+	}
+	return h[i][0].index < h[j][0].index
+}
+
+func (h txsByFee) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *txsByFee) Push(x interface{}) {
+	*h = append(*h, x.([]*indexedTx))
+}
+
+func (h *txsByFee) Pop() interface{} {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
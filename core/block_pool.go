@@ -30,11 +30,14 @@
 package core
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"sync"
 
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/common"
 	"github.com/yeeco/gyee/core/pb"
 	"github.com/yeeco/gyee/log"
 	"github.com/yeeco/gyee/p2p"
@@ -61,7 +64,8 @@ type BlockPool struct {
 	core  *Core
 	chain *BlockChain
 
-	subscriber *p2p.Subscriber
+	subscriber       *p2p.Subscriber
+	headerSubscriber *p2p.Subscriber
 
 	// chan for block with valid signature(maybe not enough)
 	blockChan chan *Block
@@ -69,7 +73,9 @@ type BlockPool struct {
 	sealChan chan *sealRequest
 
 	// pending block / request
-	blockMap map[uint64]*Block
+	//   blocks of same height kept side by side till one of them is canonical,
+	//   fork blocks competing with chain kept for TooFarBlocks
+	blockMap map[uint64]map[common.Hash]*Block
 	sealMap  map[uint64]*sealRequest
 
 	lock   sync.RWMutex
@@ -84,7 +90,7 @@ func NewBlockPool(core *Core) (*BlockPool, error) {
 		chain:     core.blockChain,
 		blockChan: make(chan *Block),
 		sealChan:  make(chan *sealRequest, 10),
-		blockMap:  make(map[uint64]*Block),
+		blockMap:  make(map[uint64]map[common.Hash]*Block),
 		sealMap:   make(map[uint64]*sealRequest),
 		quitCh:    make(chan struct{}),
 	}
@@ -98,6 +104,9 @@ func (bp *BlockPool) Start() {
 
 	bp.subscriber = p2p.NewSubscriber(bp, make(chan p2p.Message), p2p.MessageTypeBlock)
 	bp.core.node.P2pService().Register(bp.subscriber)
+	// block headers share the same msg chan
+	bp.headerSubscriber = p2p.NewSubscriber(bp, bp.subscriber.MsgChan, p2p.MessageTypeBlockHeader)
+	bp.core.node.P2pService().Register(bp.headerSubscriber)
 
 	go bp.loop()
 }
@@ -108,6 +117,7 @@ func (bp *BlockPool) Stop() {
 	log.Info("BlockPool Stop...")
 
 	bp.core.node.P2pService().UnRegister(bp.subscriber)
+	bp.core.node.P2pService().UnRegister(bp.headerSubscriber)
 
 	close(bp.quitCh)
 	bp.wg.Wait()
@@ -159,7 +169,20 @@ func (bp *BlockPool) processMsgHeader(msg p2p.Message) {
 		bp.markBadPeer(msg)
 		return
 	}
-	// TODO:
+	header := new(BlockHeader)
+	if err := rlp.DecodeBytes(h.Header, header); err != nil {
+		bp.markBadPeer(msg)
+		return
+	}
+	if ChainID(header.ChainID) != bp.chain.chainID {
+		log.Warn("processMsgHeader() chainID mismatch", "header", header.ChainID)
+		return
+	}
+	// next block would arrive with MessageTypeBlock,
+	// sync with peers if we are lagging behind
+	if header.Number > bp.chain.CurrentBlockHeight()+1 {
+		bp.core.downloader.Trigger()
+	}
 }
 
 func (bp *BlockPool) processMsgBlock(msg p2p.Message) {
@@ -183,24 +206,19 @@ func (bp *BlockPool) processMsgBlock(msg p2p.Message) {
 func (bp *BlockPool) processBlock(blk *Block) {
 	currHeight := bp.chain.CurrentBlockHeight()
 	if blk.Number() <= currHeight {
-		// TODO: refresh in chain block signature
-		return
-	}
-	if knownBlock, ok := bp.blockMap[blk.Number()]; ok {
-		if blk.Hash() != knownBlock.Hash() {
-			// TODO:
-			log.Crit("fork block!!!")
-			return
-		}
-		err := knownBlock.mergeSignature(blk)
-		if err != nil {
-			log.Warn("failed to merge signature", "blk", knownBlock, "err", err)
+		if stored := bp.chain.GetBlockByNumber(blk.Number()); stored != nil && stored.Hash() == blk.Hash() {
+			// refresh in chain block signature, served to syncing peers
+			if err := bp.chain.refreshSignature(blk); err != nil {
+				log.Debug("failed to refresh signature", "blk", blk.Number(), "err", err)
+			}
 			return
 		}
-		blk = knownBlock
-	} else {
-		bp.blockMap[blk.Number()] = blk
+		// competing with block in chain
+		blk = bp.putBlock(blk)
+		bp.resolveFork(blk)
+		return
 	}
+	blk = bp.putBlock(blk)
 
 	if blk.Number() > currHeight+1 {
 		// not next block, wait
@@ -208,42 +226,151 @@ func (bp *BlockPool) processBlock(blk *Block) {
 	}
 
 	// blk.Number() == currHeight + 1
+	bp.addReadyBlocks(currHeight)
+}
+
+// put block to pool, merge signatures if already known
+//   returns the block in pool
+func (bp *BlockPool) putBlock(blk *Block) *Block {
+	blocks, ok := bp.blockMap[blk.Number()]
+	if !ok {
+		blocks = make(map[common.Hash]*Block)
+		bp.blockMap[blk.Number()] = blocks
+	}
+	knownBlock, ok := blocks[blk.Hash()]
+	if !ok {
+		if len(blocks) > 0 {
+			log.Warn("fork block received", "H", blk.Number(), "hash", blk.Hash(),
+				"competitors", len(blocks))
+		}
+		blocks[blk.Hash()] = blk
+		return blk
+	}
+	if err := knownBlock.mergeSignature(blk); err != nil {
+		log.Warn("failed to merge signature", "blk", knownBlock, "err", err)
+	}
+	return knownBlock
+}
+
+func (bp *BlockPool) removeBlock(blk *Block) {
+	blocks := bp.blockMap[blk.Number()]
+	delete(blocks, blk.Hash())
+	if len(blocks) == 0 {
+		delete(bp.blockMap, blk.Number())
+	}
+}
+
+// add pooled blocks to chain from currHeight+1, as long as signatures are enough
+func (bp *BlockPool) addReadyBlocks(currHeight uint64) {
 	for {
-		if blk.Number() != currHeight+1 {
-			log.Crit("wrong block height", "blk", blk.Number(), "chain", bp.chain)
+		blk := bp.canonicalBlock(currHeight + 1)
+		if blk == nil {
+			// no block or not enough signature, wait
+			break
 		}
+		validatorCount := len(bp.chain.LastBlock().ValidatorAddr())
+		log.Info("signature count reached", "H", blk.Number(), "hash", blk.Hash(),
+			"sCnt", len(blk.signatureMap), "vCnt", validatorCount)
+		if err := bp.chain.AddBlock(blk); err != nil {
+			log.Warn("processBlock() add fail", "err", err)
+			return
+		}
+		// competitors kept, in case they win with more signatures
+		bp.removeBlock(blk)
+		currHeight++
+	}
+	bp.pruneBlocks(currHeight)
+}
+
+// pick block of next height with most validator signatures,
+// nil if none has enough signatures
+func (bp *BlockPool) canonicalBlock(height uint64) *Block {
+	var (
+		best           *Block
+		validatorCount = len(bp.chain.LastBlock().ValidatorAddr())
+	)
+	for _, blk := range bp.blockMap[height] {
 		// block signatures may be checked against lastBlock when received
 		if !blk.checkAgainstParent {
-			err := bp.chain.verifySignature(blk, true)
-			if err != nil {
+			if err := bp.chain.verifySignature(blk, true); err != nil {
 				log.Warn("verifySignature() verify fails", "blk", blk, "err", err)
-				break
+				bp.removeBlock(blk)
+				continue
 			}
 		}
-		// if signature was enouth
-		sigCount := len(blk.signatureMap)
-		validatorCount := len(bp.chain.LastBlock().ValidatorAddr())
-		if sigCount*3 < validatorCount*2 {
-			// not enough signature, wait
-			break
+		if !enoughSignatures(blk, validatorCount) {
+			continue
 		}
-		log.Info("signature count reached", "H", blk.Number(), "hash", blk.Hash(),
-			"sCnt", sigCount, "vCnt", validatorCount)
-		if err := bp.core.blockChain.AddBlock(blk); err != nil {
-			log.Warn("processBlock() add fail", "err", err)
-			return
+		if best == nil || preferBlock(blk, best) {
+			best = blk
 		}
-		delete(bp.blockMap, blk.Number())
+	}
+	return best
+}
 
-		currHeight++
-		var ok bool
-		blk, ok = bp.blockMap[currHeight+1]
-		if !ok {
-			break
+// check a fork block against block of same height in chain,
+// reorg chain to it if it has more validator signatures
+func (bp *BlockPool) resolveFork(blk *Block) {
+	stored := bp.chain.GetBlockByNumber(blk.Number())
+	if stored == nil {
+		return
+	}
+	if stored.ParentHash() != blk.ParentHash() {
+		// parent not canonical, wait for parent to win first
+		log.Debug("fork block parent not in chain", "H", blk.Number(), "hash", blk.Hash())
+		return
+	}
+	if err := bp.chain.verifySignature(blk, true); err != nil {
+		log.Warn("fork block verifySignature() fails", "blk", blk, "err", err)
+		bp.removeBlock(blk)
+		return
+	}
+	if err := bp.chain.verifySignature(stored, true); err != nil {
+		log.Warn("chain block verifySignature() fails", "blk", stored, "err", err)
+	}
+	validatorCount := len(stored.ValidatorAddr())
+	if !enoughSignatures(blk, validatorCount) || !preferBlock(blk, stored) {
+		return
+	}
+	log.Warn("fork block wins", "H", blk.Number(), "hash", blk.Hash(), "sCnt", len(blk.signatureMap),
+		"chainHash", stored.Hash(), "chainSCnt", len(stored.signatureMap))
+	if err := bp.chain.Reorg(blk); err != nil {
+		log.Warn("failed to reorg chain", "blk", blk, "err", err)
+		return
+	}
+	bp.removeBlock(blk)
+	// replaced block keeps competing
+	bp.putBlock(stored)
+	bp.addReadyBlocks(blk.Number())
+}
+
+// drop pooled blocks too old to compete with chain
+func (bp *BlockPool) pruneBlocks(currHeight uint64) {
+	if currHeight <= TooFarBlocks {
+		return
+	}
+	for height := range bp.blockMap {
+		if height+TooFarBlocks < currHeight {
+			delete(bp.blockMap, height)
 		}
 	}
 }
 
+// if block signatures reached 2/3 of validators
+func enoughSignatures(b *Block, validatorCount int) bool {
+	return len(b.signatureMap)*3 >= validatorCount*2
+}
+
+// fork choice between blocks of same height,
+// block with more validator signatures, or lower hash on ties
+func preferBlock(a, b *Block) bool {
+	if len(a.signatureMap) != len(b.signatureMap) {
+		return len(a.signatureMap) > len(b.signatureMap)
+	}
+	ha, hb := a.Hash(), b.Hash()
+	return bytes.Compare(ha[:], hb[:]) < 0
+}
+
 func (bp *BlockPool) handleSealRequest(req *sealRequest) {
 	currHeight := bp.chain.CurrentBlockHeight()
 	switch {
@@ -280,16 +407,12 @@ func (bp *BlockPool) handleSealRequest(req *sealRequest) {
 			log.Crit("failed to sign block", "err", err)
 		}
 		log.Info("block sealed", "txs", len(req.txs), "hash", nextBlock.Hash())
-		// merge with received signatures
-		if knownBlock, ok := bp.blockMap[nextBlock.Number()]; ok {
-			if knownBlock.Hash() != nextBlock.Hash() {
-				// TODO:
-				log.Crit("fork block!!!")
-				return
-			}
+		// merge with received signatures, competing blocks stay in pool
+		if knownBlock, ok := bp.blockMap[nextBlock.Number()][nextBlock.Hash()]; ok {
 			if err := nextBlock.mergeSignature(knownBlock); err != nil {
 				log.Warn("failed to merge signature", "blk", knownBlock, "err", err)
 			}
+			bp.removeBlock(knownBlock)
 		}
 		// insert chain
 		if err := bp.chain.AddBlock(nextBlock); err != nil {
@@ -0,0 +1,152 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/yeeco/gyee/persistent"
+)
+
+func newTestBlockPool(t *testing.T, tv *testValidators) (*BlockPool, *BlockChain) {
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	bp, err := NewBlockPool(&Core{blockChain: chain})
+	if err != nil {
+		t.Fatalf("NewBlockPool() %v", err)
+	}
+	return bp, chain
+}
+
+// build a block on parent at time offset, signed by given validators
+func (tv *testValidators) forkBlock(t *testing.T, chain *BlockChain, parent *Block, dt uint64, signers ...int) *Block {
+	b, err := chain.BuildNextBlock(parent, parent.Time()+dt, nil)
+	if err != nil {
+		t.Fatalf("BuildNextBlock() %v", err)
+	}
+	for _, i := range signers {
+		if err := b.Sign(tv.signers[i]); err != nil {
+			t.Fatalf("block.Sign() %v", err)
+		}
+	}
+	return b
+}
+
+// deliver block to pool as received from peer
+func receiveBlock(t *testing.T, bp *BlockPool, b *Block) {
+	enc, err := b.ToBytes()
+	if err != nil {
+		t.Fatalf("ToBytes() %v", err)
+	}
+	received, err := ParseBlock(enc)
+	if err != nil {
+		t.Fatalf("ParseBlock() %v", err)
+	}
+	if err := bp.chain.verifyBlock(received, false); err != nil {
+		t.Fatalf("verifyBlock() %v", err)
+	}
+	bp.processBlock(received)
+}
+
+func checkChainHead(t *testing.T, chain *BlockChain, b *Block) {
+	t.Helper()
+	last := chain.LastBlock()
+	if last.Number() != b.Number() || last.Hash() != b.Hash() {
+		t.Fatalf("chain head, need %d %v got %d %v", b.Number(), b.Hash(), last.Number(), last.Hash())
+	}
+	if stored := chain.GetBlockByNumber(b.Number()); stored == nil || stored.Hash() != b.Hash() {
+		t.Fatalf("canonical block %d mismatch", b.Number())
+	}
+	if getLastBlock(chain.storage) != b.Hash() {
+		t.Fatalf("stored last block mismatch")
+	}
+}
+
+func TestBlockPoolFork(t *testing.T) {
+	tv := newTestValidators(t, 4)
+	bp, chain := newTestBlockPool(t, tv)
+	genesis := chain.LastBlock()
+
+	a1 := tv.forkBlock(t, chain, genesis, 1, 0, 1, 2)
+	receiveBlock(t, bp, a1)
+	checkChainHead(t, chain, a1)
+
+	// competing block kept side by side, not enough to replace a1
+	b1 := tv.forkBlock(t, chain, genesis, 2, 3)
+	receiveBlock(t, bp, b1)
+	checkChainHead(t, chain, a1)
+	if len(bp.blockMap[1]) != 1 {
+		t.Fatalf("fork block not kept")
+	}
+
+	// a2 on a1 grows chain
+	a2 := tv.forkBlock(t, chain, a1, 1, 0, 1, 2)
+	receiveBlock(t, bp, a2)
+	checkChainHead(t, chain, a2)
+
+	// b1 collects more signatures than a1, chain reorgs to b1
+	receiveBlock(t, bp, tv.forkBlock(t, chain, genesis, 2, 0, 1, 2))
+	checkChainHead(t, chain, b1)
+	if chain.GetBlockByNumber(2) != nil {
+		t.Fatalf("block above reorg point still canonical")
+	}
+	if chain.GetBlockByHash(a2.Hash()) == nil {
+		t.Fatalf("dropped block should be kept by hash")
+	}
+
+	// chain grows on b1
+	b2 := tv.forkBlock(t, chain, b1, 1, 1, 2, 3)
+	receiveBlock(t, bp, b2)
+	checkChainHead(t, chain, b2)
+}
+
+func TestBlockPoolCanonicalChoice(t *testing.T) {
+	tv := newTestValidators(t, 4)
+	bp, chain := newTestBlockPool(t, tv)
+	genesis := chain.LastBlock()
+
+	// competing blocks of same signature count wait for parent
+	a1 := tv.forkBlock(t, chain, genesis, 1, 0, 1, 2)
+	c2 := tv.forkBlock(t, chain, a1, 1, 0, 1, 2)
+	d2 := tv.forkBlock(t, chain, a1, 2, 1, 2, 3)
+	e2 := tv.forkBlock(t, chain, a1, 3, 0, 1, 2, 3)
+	receiveBlock(t, bp, c2)
+	receiveBlock(t, bp, d2)
+	if chain.CurrentBlockHeight() != 0 {
+		t.Fatalf("block added without parent")
+	}
+
+	receiveBlock(t, bp, a1)
+	expect := c2
+	if preferBlock(d2, c2) {
+		expect = d2
+	}
+	checkChainHead(t, chain, expect)
+
+	// more signatures wins
+	receiveBlock(t, bp, e2)
+	checkChainHead(t, chain, e2)
+
+	// ties with chain block broken by hash, as among pooled blocks
+	f2 := tv.forkBlock(t, chain, a1, 4, 0, 1, 2, 3)
+	receiveBlock(t, bp, f2)
+	expect = e2
+	if preferBlock(f2, e2) {
+		expect = f2
+	}
+	checkChainHead(t, chain, expect)
+}
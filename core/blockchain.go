@@ -23,14 +23,16 @@ package core
 import (
 	"errors"
 	"fmt"
+	"math/big"
 	"sync"
 	"sync/atomic"
 
-	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/config"
 	"github.com/yeeco/gyee/consensus"
 	"github.com/yeeco/gyee/core/pb"
 	"github.com/yeeco/gyee/core/state"
+	"github.com/yeeco/gyee/core/yvm"
 	"github.com/yeeco/gyee/crypto"
 	"github.com/yeeco/gyee/log"
 	"github.com/yeeco/gyee/persistent"
@@ -43,8 +45,14 @@ var (
 	ErrBlockParentMissing     = errors.New("core.chain: block parent missing")
 	ErrBlockParentMismatch    = errors.New("core.chain: block parent mismatch")
 	ErrBlockSignatureMismatch = errors.New("core.chain: block signature mismatch")
+	ErrBlockNotFound          = errors.New("core.chain: block not found")
+	ErrBlockAboveHead         = errors.New("core.chain: block number above chain head")
+	ErrBlockReorgTooDeep      = errors.New("core.chain: reorg drops final block")
 )
 
+// canonical blocks deeper than MaxReorgDepth from chain head are final, never dropped by reorg
+const MaxReorgDepth = 16
+
 // BlockChain is a Data Manager that
 //   created with a Storage, for chain trie/data storage
 //   created with a Genesis block
@@ -56,6 +64,14 @@ type BlockChain struct {
 	storage persistent.Storage
 	stateDB state.Database
 	engine  consensus.Engine
+	txPool  *TransactionPool // informed of sealed txs, set while pool running
+	feed    *EventFeed       // new heads published to, nil for chain without core
+	vm      yvm.YVM          // executes contract txs
+
+	addrIndex bool // maintain address tx index
+
+	stateGC   *stateGC           // prune state of old blocks, nil for archive node
+	trieCache common.StorageSize // dirty trie nodes allowed in memory
 
 	genesis *Block
 
@@ -68,7 +84,28 @@ type BlockChain struct {
 }
 
 func NewBlockChainWithCore(core *Core) (*BlockChain, error) {
-	return NewBlockChain(ChainID(core.config.Chain.ChainID), core.storage, core.engine)
+	bc, err := NewBlockChainWithConfig(core.config, core.storage, core.engine)
+	if err != nil {
+		return nil, err
+	}
+	if core.yvm != nil {
+		bc.vm = core.yvm
+	}
+	bc.feed = core.feed
+	return bc, nil
+}
+
+// NewBlockChainWithConfig creates chain with chain options of node config
+func NewBlockChainWithConfig(conf *config.Config, storage persistent.Storage, engine consensus.Engine) (*BlockChain, error) {
+	bc, err := NewBlockChain(ChainID(conf.Chain.ChainID), storage, engine)
+	if err != nil {
+		return nil, err
+	}
+	if err := bc.setAddressIndex(conf.Chain.AddressIndex); err != nil {
+		return nil, err
+	}
+	bc.setStatePruning(conf.Chain.StatePruning, conf.Chain.StateHistory, conf.Chain.TrieCache)
+	return bc, nil
 }
 
 func NewBlockChain(chainID ChainID, storage persistent.Storage, engine consensus.Engine) (*BlockChain, error) {
@@ -84,10 +121,12 @@ func NewBlockChain(chainID ChainID, storage persistent.Storage, engine consensus
 	}
 
 	bc := &BlockChain{
-		chainID: chainID,
-		storage: storage,
-		stateDB: GetStateDB(storage),
-		engine:  engine,
+		chainID:   chainID,
+		storage:   storage,
+		stateDB:   GetStateDB(storage),
+		engine:    engine,
+		vm:        yvm.NewYVM(),
+		trieCache: DefaultTrieCache * 1024 * 1024,
 	}
 
 	bc.genesis = bc.GetBlockByNumber(0)
@@ -170,6 +209,9 @@ func (bc *BlockChain) Stop() {
 	bc.wg.Wait()
 
 	// flush caches to storage
+	if err := bc.flushState(); err != nil {
+		log.Error("failed to flush state", "err", err)
+	}
 }
 
 // reset chain to genesis block
@@ -191,17 +233,27 @@ func (bc *BlockChain) storeBlock(b *Block) error {
 	bc.chainmu.Lock()
 	defer bc.chainmu.Unlock()
 
+	return bc.writeBlock(b, bc.storage.NewBatch())
+}
+
+// write block with entries already in batch, chainmu locked
+//   txs replayed from parent if state or receipts of the block missing
+func (bc *BlockChain) writeBlock(b *Block, batch persistent.Batch) error {
 	// make chain wait for block commit
 	bc.wg.Add(1)
 	defer bc.wg.Done()
 
-	if b.stateTrie == nil {
+	if b.stateTrie == nil || b.receipts == nil {
 		var err error
 		b.stateTrie, err = bc.StateAt(b.header.StateRoot)
-		if err != nil {
-			// state root not in storage, try replay txs from parent block
+		if err != nil || (b.receipts == nil && b.header.Number > 0) {
+			// state root not in storage, or receipts not derived yet,
+			// try replay txs from parent block
 			prevBlk := bc.GetBlockByNumber(b.header.Number - 1)
 			if prevBlk == nil {
+				if err == nil {
+					err = ErrBlockParentMissing
+				}
 				return err
 			}
 			// get state for prev block
@@ -210,10 +262,13 @@ func (bc *BlockChain) storeBlock(b *Block) error {
 				return err
 			}
 			// replay txs from prev block
-			_, err = bc.replayTxs(stateTrie, b.transactions)
+			inBlockTxs, receipts, err := bc.replayTxs(stateTrie, prevBlk.ValidatorAddr(), b.header.Number, b.transactions)
 			if err != nil {
 				return err
 			}
+			if len(inBlockTxs) != len(b.transactions) {
+				return ErrBlockBodyTxsMismatch
+			}
 			// check state root hash
 			h, err := stateTrie.Commit()
 			if err != nil {
@@ -222,23 +277,70 @@ func (bc *BlockChain) storeBlock(b *Block) error {
 			if h != b.header.StateRoot {
 				return ErrBlockStateTrieMismatch
 			}
+			// check receipts root hash
+			h, err = receipts.hash()
+			if err != nil {
+				return err
+			}
+			if h != b.header.ReceiptsRoot {
+				return ErrBlockReceiptsMismatch
+			}
 			// all set
 			b.stateTrie = stateTrie
+			b.receipts = receipts
 		}
 	}
 
-	batch := bc.storage.NewBatch()
-
 	if err := b.Write(batch); err != nil {
 		return err
 	}
+	// locate txs in chain, replacing location in other blocks
+	for i, tx := range b.transactions {
+		putTxLookup(batch, *tx.Hash(), &TxLookup{
+			BlockHash: b.Hash(),
+			Number:    b.Number(),
+			Index:     uint32(i),
+		})
+	}
+	if bc.addrIndex {
+		if err := writeAddressIndex(bc.storage, batch, b); err != nil {
+			return err
+		}
+	}
 
 	// batch writing to storage
 	if err := batch.Write(); err != nil {
 		return err
 	}
 
-	return nil
+	return bc.commitState(b)
+}
+
+// verify and add next block from outside of consensus, by sync or import
+//   block already in chain is skipped
+func (bc *BlockChain) importBlock(b *Block) error {
+	if b.Number() <= bc.CurrentBlockHeight() {
+		if existing := bc.GetBlockByNumber(b.Number()); existing != nil && existing.Hash() == b.Hash() {
+			return nil
+		}
+		return ErrBlockImportMismatch
+	}
+	if err := bc.verifyBlock(b, true); err != nil {
+		log.Warn("import block verify fails", "number", b.Number(), "err", err)
+		return err
+	}
+	parent := bc.GetBlockByNumber(b.Number() - 1)
+	if parent == nil {
+		return ErrBlockParentMissing
+	}
+	// same signature threshold as in block pool
+	validatorCount := len(parent.ValidatorAddr())
+	if !enoughSignatures(b, validatorCount) {
+		log.Warn("import block signature not enough", "number", b.Number(),
+			"sCnt", len(b.signatureMap), "vCnt", validatorCount)
+		return ErrSyncSignature
+	}
+	return bc.AddBlock(b)
 }
 
 // add a checked block to block chain, as last block
@@ -261,48 +363,390 @@ func (bc *BlockChain) AddBlock(b *Block) error {
 		return err
 	}
 
+	putLastBlock(bc.storage, b.Hash())
 	bc.lastBlock.Store(b)
 
+	txs := make([]common.Hash, 0, len(b.transactions))
+	for _, tx := range b.transactions {
+		txs = append(txs, *tx.Hash())
+	}
 	if engine := bc.engine; engine != nil {
-		txs := make([]common.Hash, 0, len(b.transactions))
+		engine.OnTxSealed(b.Number(), txs)
+	}
+	if txPool := bc.txPool; txPool != nil {
+		txPool.OnTxSealed(b.Number(), b.transactions)
+	}
+	bc.feed.Send(&Event{Type: EventNewHead, Block: b})
+
+	return nil
+}
+
+// add a block with state synced from peers as last block
+//   state trie nodes expected in storage already
+//   blocks between genesis and it are not in chain, neither are receipts of its txs
+func (bc *BlockChain) insertSyncedBlock(b *Block) error {
+	if err := b.prepareTrie(bc.stateDB); err != nil {
+		return err
+	}
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	batch := bc.storage.NewBatch()
+	if err := b.Write(batch); err != nil {
+		return err
+	}
+	for i, tx := range b.transactions {
+		putTxLookup(batch, *tx.Hash(), &TxLookup{
+			BlockHash: b.Hash(),
+			Number:    b.Number(),
+			Index:     uint32(i),
+		})
+	}
+	if bc.addrIndex {
+		if err := writeAddressIndex(bc.storage, batch, b); err != nil {
+			return err
+		}
+	}
+	putLastBlock(batch, b.Hash())
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	bc.lastBlock.Store(b)
+	if gc := bc.stateGC; gc != nil {
+		gc.lastFlush = b.Number()
+	}
+
+	if txPool := bc.txPool; txPool != nil {
+		txPool.OnHeadReset(b.Number())
+	}
+	bc.feed.Send(&Event{Type: EventNewHead, Block: b})
+	return nil
+}
+
+// Reorg makes a fork block canonical at its height, as last block
+//   parent of the block must be in canonical chain
+//   blocks above the parent are dropped from canonical chain, still kept by hash,
+//   at most MaxReorgDepth of them
+//   txs of dropped blocks not sealed in the fork block are returned to tx pool
+func (bc *BlockChain) Reorg(b *Block) error {
+	if b.Number() == 0 {
+		return ErrBlockParentMissing
+	}
+	bc.chainmu.Lock()
+	oldHead, dropped, err := bc.reorg(b)
+	bc.chainmu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	log.Warn("chain reorg", "number", b.Number(), "hash", b.Hash(),
+		"oldNumber", oldHead.Number(), "oldHash", oldHead.Hash())
+
+	if txPool := bc.txPool; txPool != nil {
+		txPool.OnHeadReset(b.Number())
+		txPool.OnTxsDropped(droppedTxs(b, dropped))
+	}
+	bc.feed.Send(&Event{Type: EventNewHead, Block: b})
+	return nil
+}
+
+// store fork block and rewrite canonical chain in one batch, chainmu locked
+//   entries of dropped blocks deleted first, those of txs sealed again
+//   in the fork block are written after by writeBlock
+func (bc *BlockChain) reorg(b *Block) (*Block, []*Block, error) {
+	parent := bc.GetBlockByNumber(b.Number() - 1)
+	if parent == nil {
+		return nil, nil, ErrBlockParentMissing
+	}
+	if parent.Hash() != b.ParentHash() {
+		return nil, nil, ErrBlockParentMismatch
+	}
+	oldHead := bc.LastBlock()
+	if oldHead.Number() >= b.Number()+MaxReorgDepth {
+		return nil, nil, ErrBlockReorgTooDeep
+	}
+	dropped := bc.canonicalBlocks(b.Number(), oldHead.Number())
+
+	batch := bc.storage.NewBatch()
+	for _, blk := range dropped {
+		if err := deleteTxEntries(bc.storage, batch, blk.Hash(), blk.transactions); err != nil {
+			return nil, nil, err
+		}
+	}
+	for n := b.Number() + 1; n <= oldHead.Number(); n++ {
+		if err := batch.Del(keyBlockNum2Hash(n)); err != nil {
+			return nil, nil, err
+		}
+	}
+	if bc.addrIndex {
+		if err := deleteAddressIndex(bc.storage, batch, dropped); err != nil {
+			return nil, nil, err
+		}
+	}
+	putLastBlock(batch, b.Hash())
+	// num => hash mapping of its height rewritten with the block
+	if err := bc.writeBlock(b, batch); err != nil {
+		return nil, nil, err
+	}
+	if err := b.prepareTrie(bc.stateDB); err != nil {
+		return nil, nil, err
+	}
+	bc.lastBlock.Store(b)
+	return oldHead, dropped, nil
+}
+
+// delete lookup, tx and receipt entries of txs located in block of hash in batch
+//   txs sealed again in another block are kept
+func deleteTxEntries(getter persistent.Getter, batch persistent.Batch, hash common.Hash, txs Transactions) error {
+	for _, tx := range txs {
+		txHash := *tx.Hash()
+		lookup := getTxLookup(getter, txHash)
+		if lookup == nil || lookup.BlockHash != hash {
+			continue
+		}
+		for _, key := range [][]byte{keyTxLookup(txHash), keyTx(txHash), keyReceipt(txHash)} {
+			if err := batch.Del(key); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// canonical blocks of numbers from, to
+func (bc *BlockChain) canonicalBlocks(from, to uint64) []*Block {
+	blocks := make([]*Block, 0)
+	for n := from; n <= to; n++ {
+		if b := bc.GetBlockByNumber(n); b != nil {
+			blocks = append(blocks, b)
+		}
+	}
+	return blocks
+}
+
+// txs of dropped blocks, not sealed in fork block
+func droppedTxs(fork *Block, dropped []*Block) Transactions {
+	sealed := make(map[common.Hash]struct{}, len(fork.transactions))
+	for _, tx := range fork.transactions {
+		sealed[*tx.Hash()] = struct{}{}
+	}
+	var txs Transactions
+	for _, b := range dropped {
 		for _, tx := range b.transactions {
-			txs = append(txs, *tx.Hash())
+			if _, ok := sealed[*tx.Hash()]; !ok {
+				txs = append(txs, tx)
+			}
 		}
-		engine.OnTxSealed(b.Number(), txs)
 	}
+	return txs
+}
+
+// check if tx is sealed in canonical chain
+func (bc *BlockChain) txSealed(hash common.Hash) bool {
+	lookup := getTxLookup(bc.storage, hash)
+	return lookup != nil && getBlockNum2Hash(bc.storage, lookup.Number) == lookup.BlockHash
+}
 
+// SetHead rewinds chain to block of number, as last block
+//   canonical blocks above are removed, with lookup entries, txs, receipts
+//   and address index entries of them
+//   pruned state of the block is rebuilt by replaying from nearest lower block with state
+func (bc *BlockChain) SetHead(number uint64) error {
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	head := bc.LastBlock()
+	if number > head.Number() {
+		return ErrBlockAboveHead
+	}
+	target := bc.GetBlockByNumber(number)
+	if target == nil {
+		return ErrBlockNotFound
+	}
+	if target.stateTrie == nil {
+		if err := bc.rebuildState(target); err != nil {
+			return err
+		}
+	}
+
+	batch := bc.storage.NewBatch()
+	removed := make([]*Block, 0, head.Number()-number)
+	for n := head.Number(); n > number; n-- {
+		hash := getBlockNum2Hash(bc.storage, n)
+		if hash == common.EmptyHash {
+			continue
+		}
+		if b := bc.getBlockByHash(bc.storage, hash); b != nil {
+			removed = append(removed, b)
+		}
+		if body := getBlockBody(bc.storage, hash); body != nil {
+			txs := make(Transactions, 0, len(body.RawTransactions))
+			for _, raw := range body.RawTransactions {
+				tx := new(Transaction)
+				if err := tx.Decode(raw); err != nil {
+					return err
+				}
+				txs = append(txs, tx)
+			}
+			if err := deleteTxEntries(bc.storage, batch, hash, txs); err != nil {
+				return err
+			}
+		}
+		for _, key := range [][]byte{keyHeader(hash), keyBlockBody(hash), keyBlockHash2Num(hash), keyBlockNum2Hash(n)} {
+			if err := batch.Del(key); err != nil {
+				return err
+			}
+		}
+	}
+	// entries may be left by an index enabled before, clean up anyway
+	if err := deleteAddressIndex(bc.storage, batch, removed); err != nil {
+		return err
+	}
+	putLastBlock(batch, target.Hash())
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	bc.lastBlock.Store(target)
+	if gc := bc.stateGC; gc != nil && gc.lastFlush > number {
+		gc.lastFlush = number
+	}
+
+	log.Warn("chain head rewound", "number", target.Number(), "hash", target.Hash(),
+		"oldNumber", head.Number(), "oldHash", head.Hash())
+
+	if txPool := bc.txPool; txPool != nil {
+		txPool.OnHeadReset(target.Number())
+	}
+	bc.feed.Send(&Event{Type: EventNewHead, Block: target})
+	return nil
+}
+
+// rebuild pruned state of a canonical block, chainmu locked
+//   txs replayed from nearest lower block with state, result flushed to storage
+func (bc *BlockChain) rebuildState(b *Block) error {
+	blocks := []*Block{b}
+	base := b
+	for {
+		if base.Number() == 0 {
+			return ErrBlockNotFound
+		}
+		if base = bc.GetBlockByNumber(base.Number() - 1); base == nil {
+			return ErrBlockParentMissing
+		}
+		if base.stateTrie != nil {
+			break
+		}
+		blocks = append(blocks, base)
+	}
+	log.Info("rebuild block state", "number", b.Number(), "from", base.Number())
+
+	stateTrie, err := bc.StateAt(base.StateRoot())
+	if err != nil {
+		return err
+	}
+	parent := base
+	for i := len(blocks) - 1; i >= 0; i-- {
+		blk := blocks[i]
+		// recover tx senders
+		if err := blk.VerifyBody(); err != nil {
+			return err
+		}
+		inBlockTxs, _, err := bc.replayTxs(stateTrie, parent.ValidatorAddr(), blk.Number(), blk.transactions)
+		if err != nil {
+			return err
+		}
+		if len(inBlockTxs) != len(blk.transactions) {
+			return ErrBlockBodyTxsMismatch
+		}
+		h, err := stateTrie.Commit()
+		if err != nil {
+			return err
+		}
+		if h != blk.StateRoot() {
+			return ErrBlockStateTrieMismatch
+		}
+		parent = blk
+	}
+	if err := bc.stateDB.TrieDB().Commit(b.StateRoot(), false); err != nil {
+		return err
+	}
+	return b.prepareStateTrie(bc.stateDB)
+}
+
+// merge signatures of a received block into the same block in chain
+func (bc *BlockChain) refreshSignature(b *Block) error {
+	stored := bc.GetBlockByNumber(b.Number())
+	if stored == nil || stored.Hash() != b.Hash() {
+		return ErrBlockSignatureMismatch
+	}
+	count := len(stored.pbHeader.Signatures)
+	if err := stored.mergeSignature(b); err != nil {
+		return err
+	}
+	if len(stored.pbHeader.Signatures) == count {
+		// nothing new
+		return nil
+	}
+
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	putHeader(bc.storage, stored.pbHeader)
+	if bc.LastBlock().Hash() == stored.Hash() {
+		bc.lastBlock.Store(stored)
+	}
 	return nil
 }
 
 func (bc *BlockChain) GetBlockByNumber(number uint64) *Block {
-	hash := getBlockNum2Hash(bc.storage, number)
-	if hash == common.EmptyHash {
+	snap, err := bc.storage.NewSnapshot()
+	if err != nil {
+		log.Warn("GetBlockByNumber() snapshot failed", "err", err)
 		return nil
 	}
-	return bc.GetBlockByHash(hash)
+	defer snap.Release()
+	return bc.getBlockByNumber(snap, number)
 }
 
 func (bc *BlockChain) GetBlockByHash(hash common.Hash) *Block {
-	signedHeader := getHeader(bc.storage, hash)
+	snap, err := bc.storage.NewSnapshot()
+	if err != nil {
+		log.Warn("GetBlockByHash() snapshot failed", "err", err)
+		return nil
+	}
+	defer snap.Release()
+	return bc.getBlockByHash(snap, hash)
+}
+
+// read block from a storage snapshot, not mixing data of concurrent writes
+func (bc *BlockChain) getBlockByNumber(getter persistent.Getter, number uint64) *Block {
+	hash := getBlockNum2Hash(getter, number)
+	if hash == common.EmptyHash {
+		return nil
+	}
+	return bc.getBlockByHash(getter, hash)
+}
+
+func (bc *BlockChain) getBlockByHash(getter persistent.Getter, hash common.Hash) *Block {
+	signedHeader := getHeader(getter, hash)
 	if signedHeader == nil {
 		return nil
 	}
-	body := getBlockBody(bc.storage, hash)
+	body := getBlockBody(getter, hash)
 	if body == nil {
 		return nil
 	}
-	header := new(BlockHeader)
-	if err := rlp.DecodeBytes(signedHeader.Header, header); err != nil {
+	b := new(Block)
+	if err := b.setProto(signedHeader, body); err != nil {
 		return nil
 	}
-	b := &Block{
-		header:   header,
-		pbHeader: signedHeader,
-		body:     body,
-	}
-	if err := b.prepareTrie(bc.stateDB); err != nil {
+	if err := b.prepareConsensusTrie(bc.stateDB); err != nil {
 		return nil
 	}
+	// account state of blocks out of state history may be pruned, leaving stateTrie nil
+	if err := b.prepareStateTrie(bc.stateDB); err != nil {
+		log.Debug("block state missing", "number", b.Number(), "hash", hash, "err", err)
+	}
 	return b
 }
 
@@ -325,7 +769,7 @@ func (bc *BlockChain) BuildNextBlock(parent *Block, t uint64, txs Transactions)
 	}
 
 	// iterate txs for state changes
-	next.transactions, err = bc.replayTxs(next.stateTrie, txs)
+	next.transactions, next.receipts, err = bc.replayTxs(next.stateTrie, parent.ValidatorAddr(), next.header.Number, txs)
 	if err != nil {
 		log.Crit("replayTxs", "err", err)
 	}
@@ -340,34 +784,161 @@ func (bc *BlockChain) BuildNextBlock(parent *Block, t uint64, txs Transactions)
 	return next, nil
 }
 
-func (bc *BlockChain) replayTxs(stateTrie state.AccountTrie, txs Transactions) (Transactions, error) {
-	inBlockTxs := make(Transactions, 0, len(txs))
+// apply txs to state trie, with a receipt for each tx sealed in block
+//   txs without sender, not matching sender nonce, with fee not covering gas limit,
+//   or fee not affordable are dropped, not sealed, so they could be sealed later
+//   other txs failed to apply are still sealed, with fee charged and a failure receipt
+func (bc *BlockChain) replayTxs(stateTrie state.AccountTrie, validators []common.Address,
+	number uint64, txs Transactions) (Transactions, Receipts, error) {
+	var (
+		inBlockTxs = make(Transactions, 0, len(txs))
+		receipts   = make(Receipts, 0, len(txs))
+		fees       = new(big.Int)
+	)
 	for _, tx := range txs {
-		if tx.from == nil {
+		// txs not paying fee dropped without receipt, never charged
+		if tx.from == nil || tx.Fee().Cmp(tx.GasFee()) < 0 {
 			continue
 		}
 		accountFrom := stateTrie.GetAccount(*tx.from, false)
-		if accountFrom == nil {
-			// TODO: mark tx failure
+		if accountFrom == nil || accountFrom.Nonce() != tx.nonce ||
+			accountFrom.Balance().Cmp(tx.Fee()) < 0 {
 			continue
 		}
-		if accountFrom.Nonce() != tx.nonce {
-			// TODO: mark tx failure
+		receipt := newReceipt(tx, number, len(inBlockTxs))
+		inBlockTxs = append(inBlockTxs, tx)
+		receipts = append(receipts, receipt)
+
+		// fee charged, even if tx fails
+		accountFrom.AddNonce(1)
+		accountFrom.SubBalance(tx.Fee())
+		receipt.fee = new(big.Int).Set(tx.Fee())
+		fees.Add(fees, tx.Fee())
+
+		if tx.to == nil && tx.txType != TxTypeContractDeploy {
+			receipt.setFailed(ReceiptFailNoRecipient)
 			continue
 		}
 		if accountFrom.Balance().Cmp(tx.amount) < 0 {
-			// TODO: mark tx failure
+			receipt.setFailed(ReceiptFailInsufficientBalance)
+			continue
+		}
+		if tx.txType == TxTypeContractDeploy || tx.txType == TxTypeContractCall {
+			bc.applyContractTx(stateTrie, number, tx, receipt)
 			continue
 		}
 		accountTo := stateTrie.GetAccount(*tx.to, true)
-		// checked, update balance nonce
-		accountFrom.AddNonce(1)
 		accountFrom.SubBalance(tx.amount)
 		accountTo.AddBalance(tx.amount)
+	}
+	creditFees(stateTrie, validators, fees)
+	return inBlockTxs, receipts, nil
+}
 
-		inBlockTxs = append(inBlockTxs, tx)
+// execute contract tx with yvm, amount transferred to contract only if execution succeeded
+func (bc *BlockChain) applyContractTx(stateTrie state.AccountTrie, number uint64, tx *Transaction, receipt *Receipt) {
+	ctx := &yvm.Context{
+		State:    stateTrie,
+		ChainID:  tx.chainID,
+		Caller:   *tx.from,
+		Nonce:    tx.nonce,
+		Number:   number,
+		GasLimit: tx.gasLimit,
+	}
+	var err error
+	if tx.txType == TxTypeContractDeploy {
+		var contract common.Address
+		contract, receipt.gasUsed, err = bc.vm.Deploy(ctx, tx.payload, tx.amount)
+		if err == nil {
+			receipt.contractAddress = &contract
+		}
+	} else {
+		_, receipt.gasUsed, err = bc.vm.Call(ctx, *tx.to, tx.payload, tx.amount)
+	}
+	if err != nil {
+		log.Debug("contract tx failed", "tx", tx.Hash(), "err", err)
+		receipt.setFailed(ReceiptFailContract)
+	}
+}
+
+// share fees among validators equally, remainder to the first validator
+func creditFees(stateTrie state.AccountTrie, validators []common.Address, fees *big.Int) {
+	if fees.Sign() == 0 || len(validators) == 0 {
+		return
+	}
+	share, remainder := new(big.Int).QuoRem(fees, big.NewInt(int64(len(validators))), new(big.Int))
+	for i, addr := range validators {
+		reward := new(big.Int).Set(share)
+		if i == 0 {
+			reward.Add(reward, remainder)
+		}
+		if reward.Sign() > 0 {
+			stateTrie.GetAccount(addr, true).AddBalance(reward)
+		}
+	}
+}
+
+// TxLookup locates a tx sealed in chain
+type TxLookup struct {
+	BlockHash common.Hash
+	Number    uint64 // block number
+	Index     uint32 // tx index in block
+}
+
+// GetTransaction returns a tx sealed in canonical chain, with its location
+//   nil if not found, or the block sealing it dropped from chain
+func (bc *BlockChain) GetTransaction(hash common.Hash) (*Transaction, *TxLookup) {
+	snap, err := bc.storage.NewSnapshot()
+	if err != nil {
+		log.Warn("GetTransaction() snapshot failed", "err", err)
+		return nil, nil
+	}
+	defer snap.Release()
+
+	lookup := getTxLookup(snap, hash)
+	if lookup == nil || getBlockNum2Hash(snap, lookup.Number) != lookup.BlockHash {
+		return nil, nil
+	}
+	pbTx := getTransaction(snap, hash)
+	if pbTx == nil {
+		return nil, nil
+	}
+	tx, err := NewTransactionFromProto(pbTx)
+	if err != nil {
+		log.Warn("GetTransaction() decode failed", "hash", hash, "err", err)
+		return nil, nil
 	}
-	return inBlockTxs, nil
+	// recover sender
+	if err := tx.VerifySig(); err != nil {
+		log.Warn("GetTransaction() sig verify failed", "hash", hash, "err", err)
+	}
+	return tx, lookup
+}
+
+// get receipt of a tx sealed in chain, nil if not found
+func (bc *BlockChain) GetReceipt(hash common.Hash) *Receipt {
+	snap, err := bc.storage.NewSnapshot()
+	if err != nil {
+		log.Warn("GetReceipt() snapshot failed", "err", err)
+		return nil
+	}
+	defer snap.Release()
+
+	// receipts of blocks reorged out left in db
+	lookup := getTxLookup(snap, hash)
+	if lookup == nil || getBlockNum2Hash(snap, lookup.Number) != lookup.BlockHash {
+		return nil
+	}
+	pbReceipt := getReceipt(snap, hash)
+	if pbReceipt == nil {
+		return nil
+	}
+	receipt := new(Receipt)
+	if err := receipt.FromProto(pbReceipt); err != nil {
+		log.Warn("GetReceipt() decode failed", "hash", hash, "err", err)
+		return nil
+	}
+	return receipt
 }
 
 func (bc *BlockChain) LastBlock() *Block {
@@ -487,16 +1058,10 @@ func (bc *BlockChain) verifyTx(tx *Transaction) error {
 }
 
 func GetStateDB(storage persistent.Storage) state.Database {
-	// TODO: stateDB cache
 	stateDB := state.NewDatabaseWithCache(
 		persistent.NewTable(storage, KeyPrefixStateTrie),
-		0)
+		DefaultStateCache)
 	return stateDB
 }
 
-func (bc *BlockChain) GetChainData(kind string, key []byte) []byte {
-	// TODO:
-	return nil
-}
-
 //非验证节点，是否需要启txPool?
@@ -26,9 +26,100 @@ import (
 
 	"github.com/yeeco/gyee/common"
 	"github.com/yeeco/gyee/common/address"
+	"github.com/yeeco/gyee/crypto"
+	"github.com/yeeco/gyee/crypto/secp256k1"
 	"github.com/yeeco/gyee/persistent"
 )
 
+// validators with keys, for building signed test chains
+type testValidators struct {
+	signers []crypto.Signer
+	addrs   []common.Address
+	genesis *Genesis
+}
+
+func newTestValidators(t *testing.T, count int) *testValidators {
+	tv := new(testValidators)
+	initDist := make(map[string]*big.Int)
+	validators := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		key := secp256k1.NewPrivateKey()
+		pub, err := secp256k1.GetPublicKey(key)
+		if err != nil {
+			t.Fatalf("GetPublicKey() %v", err)
+		}
+		addr, err := address.NewAddressFromPublicKey(pub)
+		if err != nil {
+			t.Fatalf("NewAddressFromPublicKey() %v", err)
+		}
+		signer := secp256k1.NewSecp256k1Signer()
+		if err := signer.InitSigner(key); err != nil {
+			t.Fatalf("InitSigner() %v", err)
+		}
+		tv.signers = append(tv.signers, signer)
+		tv.addrs = append(tv.addrs, *addr.CommonAddress())
+		initDist[addr.String()] = big.NewInt(1000000000)
+		validators = append(validators, addr.String())
+	}
+	genesis, err := NewGenesis(TestNetID, initDist, validators)
+	if err != nil {
+		t.Fatalf("NewGenesis() %v", err)
+	}
+	tv.genesis = genesis
+	return tv
+}
+
+// new chain on storage with validators genesis
+func (tv *testValidators) newChain(t *testing.T, storage persistent.Storage) *BlockChain {
+	if _, err := tv.genesis.Commit(GetStateDB(storage), storage); err != nil {
+		t.Fatalf("genesis.Commit() %v", err)
+	}
+	chain, err := NewBlockChain(TestNetID, storage, nil)
+	if err != nil {
+		t.Fatalf("NewBlockChain() %v", err)
+	}
+	return chain
+}
+
+// signed tx transferring amount from validator i to validator j
+func (tv *testValidators) newTx(t *testing.T, i, j int, nonce uint64, amount int64) *Transaction {
+	tx := NewTransaction(uint32(TestNetID), nonce, &tv.addrs[j], big.NewInt(amount))
+	if err := tx.Sign(tv.signers[i]); err != nil {
+		t.Fatalf("tx.Sign() %v", err)
+	}
+	if err := tx.VerifySig(); err != nil {
+		t.Fatalf("tx.VerifySig() %v", err)
+	}
+	return tx
+}
+
+// build next block with txs, signed by all validators
+func (tv *testValidators) nextBlock(t *testing.T, chain *BlockChain, parent *Block, txs Transactions) *Block {
+	b, err := chain.BuildNextBlock(parent, parent.Time()+1, txs)
+	if err != nil {
+		t.Fatalf("BuildNextBlock() %v", err)
+	}
+	for _, signer := range tv.signers {
+		if err := b.Sign(signer); err != nil {
+			t.Fatalf("block.Sign() %v", err)
+		}
+	}
+	return b
+}
+
+// grow chain by count blocks, each with a transfer from validator 0
+func (tv *testValidators) growChain(t *testing.T, chain *BlockChain, count int) {
+	for i := 0; i < count; i++ {
+		last := chain.LastBlock()
+		nonce := last.stateTrie.GetAccount(tv.addrs[0], false).Nonce()
+		tx := tv.newTx(t, 0, 1, nonce, 1)
+		b := tv.nextBlock(t, chain, last, Transactions{tx})
+		if err := chain.AddBlock(b); err != nil {
+			t.Fatalf("AddBlock() %v", err)
+		}
+	}
+}
+
 func TestNewBlockChain(t *testing.T) {
 	storage := persistent.NewMemoryStorage()
 	_, err := NewBlockChain(MainNetID, storage, nil)
@@ -114,6 +205,302 @@ func TestBlockChainGrow(t *testing.T) {
 	chain.Stop()
 }
 
+func TestBlockChainReceipts(t *testing.T) {
+	chain, err := NewBlockChain(TestNetID, persistent.NewMemoryStorage(), nil)
+	if err != nil {
+		t.Fatalf("newChain() %v", err)
+	}
+	account0, err := address.AddressParse("0105cfa04d12fb46fcea51d22cf1f340631bbe930dc0e026ba21")
+	if err != nil {
+		t.Fatalf("AddressParse %v", err)
+	}
+	addrFrom := account0.CommonAddress()
+	addrUnknown := &common.Address{0xff}
+	balance := chain.LastBlock().stateTrie.GetAccount(*addrFrom, false).Balance()
+
+	newTx := func(from *common.Address, nonce uint64, amount, fee *big.Int) *Transaction {
+		tx := NewTransactionWithFee(uint32(TestNetID), nonce, &common.Address{0x01}, amount, fee)
+		tx.from = from
+		return tx
+	}
+	txs := Transactions{
+		newTx(addrFrom, 0, big.NewInt(1), big.NewInt(1)),
+		newTx(addrFrom, 1, balance, big.NewInt(1)),
+		// dropped
+		newTx(addrFrom, 5, big.NewInt(1), big.NewInt(1)),
+		newTx(addrUnknown, 0, big.NewInt(2), big.NewInt(1)),
+		newTx(addrFrom, 2, big.NewInt(1), balance),
+	}
+	expected := []ReceiptFailReason{
+		ReceiptFailNone,
+		ReceiptFailInsufficientBalance,
+	}
+	block, err := chain.BuildNextBlock(chain.LastBlock(), 0, txs)
+	if err != nil {
+		t.Fatalf("BuildNextBlock() %v", err)
+	}
+	if len(block.transactions) != len(expected) {
+		t.Fatalf("txs paying fee should be sealed, got %d", len(block.transactions))
+	}
+	if block.ReceiptsRoot() == common.EmptyHash {
+		t.Fatalf("empty receipts root")
+	}
+
+	// a peer without the receipts replays txs and checks receipts root
+	replayed := &Block{
+		header:       block.header,
+		pbHeader:     block.pbHeader,
+		body:         block.body,
+		transactions: block.transactions,
+	}
+	if err := chain.AddBlock(replayed); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+	for i, reason := range expected {
+		receipt := chain.GetReceipt(*txs[i].Hash())
+		if receipt == nil {
+			t.Fatalf("receipt missing for tx %d", i)
+		}
+		if receipt.FailReason() != reason || receipt.Succeeded() != (reason == ReceiptFailNone) ||
+			receipt.Fee().Cmp(big.NewInt(1)) != 0 {
+			t.Errorf("tx %d wrong receipt %v", i, receipt)
+		}
+		if receipt.BlockNumber() != block.Number() || receipt.Index() != uint32(i) {
+			t.Errorf("tx %d wrong receipt location %v", i, receipt)
+		}
+	}
+	// dropped txs left unsealed, to be sealed later
+	for i, tx := range txs[len(expected):] {
+		if chain.GetReceipt(*tx.Hash()) != nil || hasTransaction(chain.storage, *tx.Hash()) {
+			t.Errorf("dropped tx %d sealed", i+len(expected))
+		}
+	}
+	if got := block.stateTrie.GetAccount(*addrFrom, false).Nonce(); got != 2 {
+		t.Errorf("sender nonce, need 2 got %d", got)
+	}
+
+	// tampered receipts root should be rejected
+	header := CopyHeader(block.header)
+	header.Number++
+	header.ParentHash = block.Hash()
+	header.ReceiptsRoot = common.Hash{0x01}
+	if err := chain.AddBlock(NewBlock(header, nil)); err != ErrBlockReceiptsMismatch {
+		t.Errorf("tampered receipts root, got %v", err)
+	}
+}
+
+func TestBlockChainFees(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	parent := chain.LastBlock()
+	balanceOf := func(b *Block, i int) *big.Int {
+		return b.stateTrie.GetAccount(tv.addrs[i], false).Balance()
+	}
+
+	tx0 := NewTransactionWithFee(uint32(TestNetID), 0, &tv.addrs[1], big.NewInt(100), big.NewInt(10))
+	tx1 := NewTransactionWithFee(uint32(TestNetID), 1, &tv.addrs[1], balanceOf(parent, 0), big.NewInt(1))
+	for _, tx := range []*Transaction{tx0, tx1} {
+		tx.from = &tv.addrs[0]
+	}
+	b := tv.nextBlock(t, chain, parent, Transactions{tx0, tx1})
+	if err := chain.AddBlock(b); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+
+	// fee of 10 + 1 shared by 3 validators, remainder to the first
+	rewards := []int64{5, 3, 3}
+	deltas := []int64{-111, 100, 0}
+	for i := range tv.addrs {
+		expect := new(big.Int).Add(balanceOf(parent, i), big.NewInt(rewards[i]+deltas[i]))
+		if got := balanceOf(b, i); got.Cmp(expect) != 0 {
+			t.Errorf("validator %d balance, need %v got %v", i, expect, got)
+		}
+	}
+	if r := chain.GetReceipt(*tx0.Hash()); r == nil || r.Fee().Cmp(big.NewInt(10)) != 0 {
+		t.Errorf("tx0 receipt fee, got %v", r)
+	}
+	// amount affordable, amount + fee not, fee charged
+	if r := chain.GetReceipt(*tx1.Hash()); r == nil || r.FailReason() != ReceiptFailInsufficientBalance || r.Fee().Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("tx1 receipt, got %v", r)
+	}
+}
+
+func TestBlockChainReorg(t *testing.T) {
+	tv := newTestValidators(t, 4)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	genesis := chain.LastBlock()
+	tv.growChain(t, chain, 3)
+	head := chain.LastBlock()
+
+	// fork on a dropped parent is not accepted
+	orphan := tv.nextBlock(t, chain, chain.GetBlockByNumber(2), nil)
+	fork := tv.nextBlock(t, chain, chain.GetBlockByNumber(1), nil)
+	if err := chain.Reorg(fork); err != nil {
+		t.Fatalf("Reorg() %v", err)
+	}
+	if chain.CurrentBlockHeight() != 2 || chain.LastBlock().Hash() != fork.Hash() {
+		t.Fatalf("reorg head mismatch")
+	}
+	if chain.GetBlockByNumber(3) != nil || chain.GetBlockByHash(head.Hash()) == nil {
+		t.Fatalf("reorg num => hash mapping mismatch")
+	}
+	// entries of tx in dropped block 2 removed with the canonical rewrite
+	tx2 := *tv.newTx(t, 0, 1, 1, 1).Hash()
+	if getTxLookup(chain.storage, tx2) != nil || getReceipt(chain.storage, tx2) != nil ||
+		hasTransaction(chain.storage, tx2) {
+		t.Errorf("entries of dropped tx left")
+	}
+	if err := chain.Reorg(orphan); err != ErrBlockParentMismatch {
+		t.Errorf("reorg on dropped parent, got %v", err)
+	}
+	if err := chain.Reorg(genesis); err != ErrBlockParentMissing {
+		t.Errorf("reorg genesis, got %v", err)
+	}
+
+	// state follows the fork, tx of dropped block 2 not applied
+	sender := chain.LastBlock().stateTrie.GetAccount(tv.addrs[0], false)
+	if sender.Nonce() != 1 {
+		t.Errorf("sender nonce after reorg, need 1 got %d", sender.Nonce())
+	}
+
+	// final blocks not dropped
+	tv.growChain(t, chain, MaxReorgDepth)
+	deep := tv.nextBlock(t, chain, chain.GetBlockByNumber(1), nil)
+	if err := chain.Reorg(deep); err != ErrBlockReorgTooDeep {
+		t.Errorf("reorg of final block, got %v", err)
+	}
+}
+
+func TestBlockChainGetTransaction(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	tv.growChain(t, chain, 1)
+	tx0 := tv.newTx(t, 0, 1, 1, 5)
+	tx1 := tv.newTx(t, 1, 2, 0, 7)
+	b2 := tv.nextBlock(t, chain, chain.LastBlock(), Transactions{tx0, tx1})
+	if err := chain.AddBlock(b2); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+
+	tx, lookup := chain.GetTransaction(*tx1.Hash())
+	if tx == nil || lookup == nil {
+		t.Fatalf("GetTransaction() sealed tx not found")
+	}
+	if *tx.Hash() != *tx1.Hash() || tx.from == nil || *tx.from != tv.addrs[1] {
+		t.Errorf("GetTransaction() tx mismatch %v", tx)
+	}
+	expected := TxLookup{BlockHash: b2.Hash(), Number: 2, Index: 1}
+	if *lookup != expected {
+		t.Errorf("GetTransaction() location need %v got %v", expected, *lookup)
+	}
+	if tx, lookup := chain.GetTransaction(common.EmptyHash); tx != nil || lookup != nil {
+		t.Errorf("GetTransaction() unknown tx found")
+	}
+
+	// tx of block dropped by reorg not in chain
+	fork := tv.nextBlock(t, chain, chain.GetBlockByNumber(1), nil)
+	if err := chain.Reorg(fork); err != nil {
+		t.Fatalf("Reorg() %v", err)
+	}
+	if tx, lookup := chain.GetTransaction(*tx1.Hash()); tx != nil || lookup != nil {
+		t.Errorf("GetTransaction() tx of dropped block found")
+	}
+}
+
+func TestBlockChainSetHead(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	storage := persistent.NewMemoryStorage()
+	chain := tv.newChain(t, storage)
+	if err := chain.setAddressIndex(true); err != nil {
+		t.Fatalf("setAddressIndex() %v", err)
+	}
+	tv.growChain(t, chain, 4)
+	chain.feed = NewEventFeed()
+	sub := chain.feed.Subscribe(1, EventNewHead)
+	defer sub.Unsubscribe()
+	b3 := chain.GetBlockByNumber(3)
+	tx3 := tv.newTx(t, 0, 1, 2, 1) // sealed in block 3 by growChain
+	if tx, _ := chain.GetTransaction(*tx3.Hash()); tx == nil {
+		t.Fatalf("GetTransaction() tx of block 3 not found")
+	}
+
+	if err := chain.SetHead(5); err != ErrBlockAboveHead {
+		t.Errorf("SetHead() above head, got %v", err)
+	}
+	if err := chain.SetHead(1); err != nil {
+		t.Fatalf("SetHead() %v", err)
+	}
+	if chain.CurrentBlockHeight() != 1 || chain.GetBlockByNumber(2) != nil {
+		t.Fatalf("SetHead() head mismatch")
+	}
+	if chain.GetBlockByHash(b3.Hash()) != nil {
+		t.Errorf("SetHead() block above head not removed")
+	}
+	if tx, _ := chain.GetTransaction(*tx3.Hash()); tx != nil || hasTransaction(storage, *tx3.Hash()) {
+		t.Errorf("SetHead() tx above head not removed")
+	}
+	if chain.GetReceipt(*tx3.Hash()) != nil {
+		t.Errorf("SetHead() receipt above head not removed")
+	}
+	if nonce := chain.LastBlock().stateTrie.GetAccount(tv.addrs[0], false).Nonce(); nonce != 1 {
+		t.Errorf("SetHead() state nonce need 1 got %d", nonce)
+	}
+	if count := getAddressTxCount(storage, tv.addrs[0]); count != 1 {
+		t.Errorf("SetHead() index entries of sender, need 1 got %d", count)
+	}
+	checkAddressTxs(t, chain, tv.addrs[0], 0, 10, addressTx(t, chain.LastBlock(), 0, AddressTxSent))
+	select {
+	case ev := <-sub.Chan():
+		if ev.Block.Number() != 1 {
+			t.Errorf("new head got %d", ev.Block.Number())
+		}
+	default:
+		t.Errorf("new head not published")
+	}
+
+	// rewound head persisted, chain grows on it
+	reloaded, err := NewBlockChain(TestNetID, storage, nil)
+	if err != nil {
+		t.Fatalf("NewBlockChain() %v", err)
+	}
+	if reloaded.CurrentBlockHeight() != 1 {
+		t.Errorf("reloaded head need 1 got %d", reloaded.CurrentBlockHeight())
+	}
+	tv.growChain(t, reloaded, 1)
+	if reloaded.CurrentBlockHeight() != 2 {
+		t.Errorf("grow after SetHead() failed")
+	}
+}
+
+func TestOrganizeTxsByFee(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	newTx := func(from int, nonce uint64, fee int64) *Transaction {
+		tx := NewTransactionWithFee(uint32(TestNetID), nonce, &tv.addrs[0], big.NewInt(1), big.NewInt(fee))
+		tx.from = &tv.addrs[from]
+		return tx
+	}
+	var (
+		a0 = newTx(0, 0, 1)
+		a1 = newTx(0, 1, 9)
+		b0 = newTx(1, 0, 5)
+		b1 = newTx(1, 1, 5)
+		b3 = newTx(1, 3, 50) // nonce gap
+		c0 = newTx(2, 0, 5)
+	)
+	output := organizeTxs(chain.LastBlock().stateTrie, Transactions{b3, a1, c0, b1, a0, b0})
+	// same fee goes by input order, a1 waits for a0 of low fee, b3 dropped for gap
+	expect := Transactions{c0, b0, b1, a0, a1}
+	if len(output) != len(expect) {
+		t.Fatalf("organized txs, need %v got %v", expect, output)
+	}
+	for i := range expect {
+		if output[i] != expect[i] {
+			t.Fatalf("organized txs, need %v got %v", expect, output)
+		}
+	}
+}
+
 func benchAddBlock(b *testing.B, storage persistent.Storage, cnt int) {
 	if err := prepareStorage(storage, TestNetID); err != nil {
 		b.Fatalf("prepareStorage() failed %v", err)
@@ -0,0 +1,151 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/core/pb"
+	"github.com/yeeco/gyee/log"
+)
+
+// Kinds of chain data exchanged between peers,
+// requested with p2p.Service.GetChainInfo(kind, key),
+// served by BlockChain.GetChainData(kind, key)
+const (
+	ChainDataLastBlock = "lastBlock" // nil => encoded SignedBlockHeader of last block
+	ChainDataHeader    = "header"    // block number / block hash => encoded SignedBlockHeader
+	ChainDataHeaders   = "headers"   // from number + count => encoded SignedBlockHeaders
+	ChainDataBody      = "body"      // block hash => encoded BlockBody
+	ChainDataTx        = "tx"        // tx hash => encoded Transaction
+	ChainDataTrieNode  = "trieNode"  // state trie node hash => trie node
+)
+
+// max block headers served / requested in a single ChainDataHeaders request
+const MaxHeaderFetch = 128
+
+var (
+	ErrChainDataKey = errors.New("core.chainData: malformed key")
+)
+
+func chainDataHeadersKey(from uint64, count uint32) []byte {
+	key := make([]byte, 12)
+	binary.BigEndian.PutUint64(key, from)
+	binary.BigEndian.PutUint32(key[8:], count)
+	return key
+}
+
+func parseChainDataHeadersKey(key []byte) (from uint64, count uint32, err error) {
+	if len(key) != 12 {
+		return 0, 0, ErrChainDataKey
+	}
+	from = binary.BigEndian.Uint64(key)
+	count = binary.BigEndian.Uint32(key[8:])
+	if count > MaxHeaderFetch {
+		count = MaxHeaderFetch
+	}
+	return from, count, nil
+}
+
+// GetChainData serves chain data of kind for peers, implements p2p.ChainProvider
+//   returns nil if kind unknown, key malformed or data not found
+func (bc *BlockChain) GetChainData(kind string, key []byte) []byte {
+	switch kind {
+	case ChainDataLastBlock:
+		return encodeChainData(kind, bc.LastBlock().pbHeader)
+	case ChainDataHeader:
+		var hash common.Hash
+		switch len(key) {
+		case 8:
+			hash = getBlockNum2Hash(bc.storage, binary.BigEndian.Uint64(key))
+		case common.HashLength:
+			hash = common.BytesToHash(key)
+		default:
+			return nil
+		}
+		if header := getHeader(bc.storage, hash); header != nil {
+			return encodeChainData(kind, header)
+		}
+	case ChainDataHeaders:
+		if headers := bc.getHeaders(key); headers != nil {
+			return encodeChainData(kind, headers)
+		}
+	case ChainDataBody:
+		if len(key) != common.HashLength {
+			return nil
+		}
+		if body := getBlockBody(bc.storage, common.BytesToHash(key)); body != nil {
+			return encodeChainData(kind, body)
+		}
+	case ChainDataTx:
+		if len(key) != common.HashLength {
+			return nil
+		}
+		if tx := getTransaction(bc.storage, common.BytesToHash(key)); tx != nil {
+			return encodeChainData(kind, tx)
+		}
+	case ChainDataTrieNode:
+		if len(key) != common.HashLength {
+			return nil
+		}
+		if node, err := bc.stateDB.TrieDB().Node(common.BytesToHash(key)); err == nil {
+			return node
+		}
+	default:
+		log.Debug("unknown chain data kind", "kind", kind)
+	}
+	return nil
+}
+
+// signed headers in range, stops at first missing block
+func (bc *BlockChain) getHeaders(key []byte) *corepb.SignedBlockHeaders {
+	from, count, err := parseChainDataHeadersKey(key)
+	if err != nil {
+		return nil
+	}
+	snap, err := bc.storage.NewSnapshot()
+	if err != nil {
+		return nil
+	}
+	defer snap.Release()
+
+	msg := new(corepb.SignedBlockHeaders)
+	for n := from; n < from+uint64(count); n++ {
+		header := getHeader(snap, getBlockNum2Hash(snap, n))
+		if header == nil {
+			break
+		}
+		msg.Headers = append(msg.Headers, header)
+	}
+	if len(msg.Headers) == 0 {
+		return nil
+	}
+	return msg
+}
+
+func encodeChainData(kind string, msg proto.Message) []byte {
+	enc, err := proto.Marshal(msg)
+	if err != nil {
+		log.Error("encodeChainData()", "kind", kind, "err", err)
+		return nil
+	}
+	return enc
+}
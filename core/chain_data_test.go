@@ -0,0 +1,108 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/core/pb"
+	"github.com/yeeco/gyee/persistent"
+)
+
+func TestChainDataProvider(t *testing.T) {
+	tv := newTestValidators(t, 4)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	tv.growChain(t, chain, 3)
+	b := chain.GetBlockByNumber(2)
+	hash := b.Hash()
+
+	// last block
+	header := new(corepb.SignedBlockHeader)
+	if err := proto.Unmarshal(chain.GetChainData(ChainDataLastBlock, nil), header); err != nil {
+		t.Fatalf("lastBlock %v", err)
+	}
+	if !bytes.Equal(header.Header, chain.LastBlock().pbHeader.Header) {
+		t.Fatalf("lastBlock header mismatch")
+	}
+
+	// header by number and by hash
+	numKey := make([]byte, 8)
+	binary.BigEndian.PutUint64(numKey, 2)
+	byNum := chain.GetChainData(ChainDataHeader, numKey)
+	byHash := chain.GetChainData(ChainDataHeader, hash[:])
+	if len(byNum) == 0 || !bytes.Equal(byNum, byHash) {
+		t.Fatalf("header by number / hash mismatch")
+	}
+	if err := proto.Unmarshal(byNum, header); err != nil {
+		t.Fatalf("header %v", err)
+	}
+	if len(header.Signatures) != len(tv.signers) {
+		t.Fatalf("header signatures, need %d got %d", len(tv.signers), len(header.Signatures))
+	}
+
+	// headers range, truncated at chain head
+	headers := new(corepb.SignedBlockHeaders)
+	if err := proto.Unmarshal(chain.GetChainData(ChainDataHeaders, chainDataHeadersKey(1, 10)), headers); err != nil {
+		t.Fatalf("headers %v", err)
+	}
+	if len(headers.Headers) != 3 {
+		t.Fatalf("headers count, need 3 got %d", len(headers.Headers))
+	}
+
+	// body
+	body := new(corepb.BlockBody)
+	if err := proto.Unmarshal(chain.GetChainData(ChainDataBody, hash[:]), body); err != nil {
+		t.Fatalf("body %v", err)
+	}
+	if len(body.RawTransactions) != 1 {
+		t.Fatalf("body txs, need 1 got %d", len(body.RawTransactions))
+	}
+
+	// tx
+	sealed := new(Transaction)
+	if err := sealed.Decode(body.RawTransactions[0]); err != nil {
+		t.Fatalf("sealed tx %v", err)
+	}
+	tx := new(Transaction)
+	if err := tx.Decode(chain.GetChainData(ChainDataTx, sealed.Hash()[:])); err != nil {
+		t.Fatalf("tx %v", err)
+	}
+	if *tx.Hash() != *sealed.Hash() {
+		t.Fatalf("tx hash mismatch")
+	}
+
+	// state trie root node
+	root := b.StateRoot()
+	if len(chain.GetChainData(ChainDataTrieNode, root[:])) == 0 {
+		t.Fatalf("trie node not found")
+	}
+
+	// missing / malformed
+	if data := chain.GetChainData(ChainDataBody, make([]byte, 32)); data != nil {
+		t.Fatalf("unexpected body for unknown hash")
+	}
+	if data := chain.GetChainData(ChainDataHeader, []byte{1}); data != nil {
+		t.Fatalf("unexpected header for malformed key")
+	}
+	if data := chain.GetChainData("unknown", nil); data != nil {
+		t.Fatalf("unexpected data for unknown kind")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/yeeco/gyee/log"
+)
+
+// Exported chain is a sequence of block records,
+//   each a 4 bytes big endian length followed by Block.ToBytes()
+
+// upper limit of a block record accepted on import
+const MaxBlockRecordSize = 32 * 1024 * 1024
+
+var (
+	ErrBlockRecordTooLarge = errors.New("core.chain: block record too large")
+	ErrBlockImportMismatch = errors.New("core.chain: imported block mismatch with chain")
+	ErrBlockRangeInvalid   = errors.New("core.chain: invalid block range")
+)
+
+// ExportChain writes canonical blocks numbered from..to to w
+//   returns number of blocks written
+func (bc *BlockChain) ExportChain(w io.Writer, from, to uint64) (int, error) {
+	if from > to || to > bc.CurrentBlockHeight() {
+		return 0, ErrBlockRangeInvalid
+	}
+	// blocks read from a snapshot, unaffected by chain growing / rewinding while exporting
+	snap, err := bc.storage.NewSnapshot()
+	if err != nil {
+		return 0, err
+	}
+	defer snap.Release()
+
+	count := 0
+	for n := from; n <= to; n++ {
+		b := bc.getBlockByNumber(snap, n)
+		if b == nil {
+			return count, ErrBlockNotFound
+		}
+		enc, err := b.ToBytes()
+		if err != nil {
+			return count, err
+		}
+		var size [4]byte
+		binary.BigEndian.PutUint32(size[:], uint32(len(enc)))
+		if _, err := w.Write(size[:]); err != nil {
+			return count, err
+		}
+		if _, err := w.Write(enc); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
+
+// ImportChain adds blocks read from r to chain, checked as blocks synced from peers
+//   blocks already in chain are skipped, returns number of blocks added
+func (bc *BlockChain) ImportChain(r io.Reader) (int, error) {
+	count := 0
+	for {
+		var size [4]byte
+		if _, err := io.ReadFull(r, size[:]); err != nil {
+			if err == io.EOF {
+				return count, nil
+			}
+			return count, err
+		}
+		length := binary.BigEndian.Uint32(size[:])
+		if length > MaxBlockRecordSize {
+			return count, ErrBlockRecordTooLarge
+		}
+		enc := make([]byte, length)
+		if _, err := io.ReadFull(r, enc); err != nil {
+			return count, err
+		}
+		b, err := ParseBlock(enc)
+		if err != nil {
+			return count, err
+		}
+		known := b.Number() <= bc.CurrentBlockHeight()
+		if err := bc.importBlock(b); err != nil {
+			log.Warn("import block failed", "number", b.Number(), "hash", b.Hash(), "err", err)
+			return count, err
+		}
+		if !known {
+			count++
+		}
+	}
+}
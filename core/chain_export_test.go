@@ -0,0 +1,79 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/yeeco/gyee/persistent"
+)
+
+func TestChainExportImport(t *testing.T) {
+	tv := newTestValidators(t, 4)
+	source := tv.newChain(t, persistent.NewMemoryStorage())
+	tv.growChain(t, source, 5)
+
+	if _, err := source.ExportChain(new(bytes.Buffer), 3, 6); err != ErrBlockRangeInvalid {
+		t.Errorf("export above head, got %v", err)
+	}
+	var buf bytes.Buffer
+	if count, err := source.ExportChain(&buf, 0, 5); err != nil || count != 6 {
+		t.Fatalf("ExportChain() %d %v", count, err)
+	}
+	exported := buf.Bytes()
+
+	target := tv.newChain(t, persistent.NewMemoryStorage())
+	if count, err := target.ImportChain(bytes.NewReader(exported)); err != nil || count != 5 {
+		t.Fatalf("ImportChain() %d %v", count, err)
+	}
+	if target.LastBlock().Hash() != source.LastBlock().Hash() {
+		t.Fatalf("imported head mismatch")
+	}
+	// known blocks skipped
+	if count, err := target.ImportChain(bytes.NewReader(exported)); err != nil || count != 0 {
+		t.Errorf("re-import %d %v", count, err)
+	}
+
+	// truncated record
+	other := tv.newChain(t, persistent.NewMemoryStorage())
+	if _, err := other.ImportChain(bytes.NewReader(exported[:len(exported)-1])); err != io.ErrUnexpectedEOF {
+		t.Errorf("import truncated, got %v", err)
+	}
+	if other.CurrentBlockHeight() != 4 {
+		t.Errorf("blocks before truncated record need imported, height %d", other.CurrentBlockHeight())
+	}
+
+	// unsigned blocks rejected
+	unsigned := tv.newChain(t, persistent.NewMemoryStorage())
+	b, err := unsigned.BuildNextBlock(unsigned.LastBlock(), 1, nil)
+	if err != nil {
+		t.Fatalf("BuildNextBlock() %v", err)
+	}
+	enc, err := b.ToBytes()
+	if err != nil {
+		t.Fatalf("ToBytes() %v", err)
+	}
+	record := make([]byte, 4, 4+len(enc))
+	binary.BigEndian.PutUint32(record, uint32(len(enc)))
+	if _, err := unsigned.ImportChain(bytes.NewReader(append(record, enc...))); err == nil {
+		t.Errorf("unsigned block imported")
+	}
+}
@@ -33,16 +33,25 @@ import (
 const (
 	KeyChainID = "ChainID"
 
+	KeySchemaVersion = "SchemaVersion" // version of storage layout
+
 	KeyLastBlock = "LastBlock"
 
+	KeyAddressIndexed = "AddrIndexed" // set if address tx index covers all blocks
+
 	KeyPrefixStateTrie = "sTrie-" // stateTrie Hash => trie node
 
-	KeyPrefixTx     = "tx-"   // txHash => encodedTx
-	KeyPrefixHeader = "blkH-" // blockHash => encodedBlockHeader
-	KeyPrefixBody   = "blkB-" // blockHash => encodedBlockBody
+	KeyPrefixTx       = "tx-"   // txHash => encodedTx
+	KeyPrefixTxLookup = "txl-"  // txHash => blockHash, blockNum, tx index
+	KeyPrefixReceipt  = "rcpt-" // txHash => encodedReceipt
+	KeyPrefixHeader   = "blkH-" // blockHash => encodedBlockHeader
+	KeyPrefixBody     = "blkB-" // blockHash => encodedBlockBody
 
 	KeyPrefixBlockNum2Hash = "bn2h-" // blockNum => blockHash
 	KeyPrefixBlockHash2Num = "bh2n-" // blockHash => blockNum
+
+	KeyPrefixAddressTxCount = "atxN-" // address => number of indexed txs
+	KeyPrefixAddressTx      = "atx-"  // address + seq => encoded AddressTx
 )
 
 func prepareStorage(storage persistent.Storage, id ChainID) error {
@@ -60,6 +69,10 @@ func prepareStorage(storage persistent.Storage, id ChainID) error {
 				return ErrBlockChainIDMismatch
 			}
 		} else {
+			// new storage created with current layout
+			if err := putSchemaVersion(storage, SchemaVersion()); err != nil {
+				return err
+			}
 			encChainID := make([]byte, 4)
 			binary.BigEndian.PutUint32(encChainID, uint32(id))
 			if err := storage.Put(key, encChainID); err != nil {
@@ -67,7 +80,7 @@ func prepareStorage(storage persistent.Storage, id ChainID) error {
 			}
 		}
 	}
-	return nil
+	return migrateStorage(storage)
 }
 
 func getLastBlock(getter persistent.Getter) common.Hash {
@@ -160,10 +173,86 @@ func hasTransaction(getter persistent.Getter, hash common.Hash) bool {
 	return has
 }
 
+func getTransaction(getter persistent.Getter, hash common.Hash) *corepb.Transaction {
+	msg := new(corepb.Transaction)
+	if err := getProtoMsg(getter, keyTx(hash), msg); err != nil {
+		if err != persistent.ErrKeyNotFound {
+			log.Error("getTransaction()", "hash", hash, "err", err)
+		}
+		return nil
+	}
+	return msg
+}
+
 func putTransaction(putter persistent.Putter, hash common.Hash, tx *corepb.Transaction) {
 	putProtoMsg(putter, keyTx(hash), tx)
 }
 
+func getTxLookup(getter persistent.Getter, hash common.Hash) *TxLookup {
+	enc, _ := getter.Get(keyTxLookup(hash))
+	if len(enc) != common.HashLength+8+4 {
+		return nil
+	}
+	lookup := &TxLookup{
+		Number: binary.BigEndian.Uint64(enc[common.HashLength:]),
+		Index:  binary.BigEndian.Uint32(enc[common.HashLength+8:]),
+	}
+	lookup.BlockHash.SetBytes(enc[:common.HashLength])
+	return lookup
+}
+
+func putTxLookup(putter persistent.Putter, hash common.Hash, lookup *TxLookup) {
+	buf := make([]byte, common.HashLength+8+4)
+	copy(buf, lookup.BlockHash[:])
+	binary.BigEndian.PutUint64(buf[common.HashLength:], lookup.Number)
+	binary.BigEndian.PutUint32(buf[common.HashLength+8:], lookup.Index)
+	if err := putter.Put(keyTxLookup(hash), buf); err != nil {
+		log.Crit("putTxLookup()", err)
+	}
+}
+
+func getReceipt(getter persistent.Getter, hash common.Hash) *corepb.Receipt {
+	msg := new(corepb.Receipt)
+	if err := getProtoMsg(getter, keyReceipt(hash), msg); err != nil {
+		if err != persistent.ErrKeyNotFound {
+			log.Error("getReceipt()", "hash", hash, "err", err)
+		}
+		return nil
+	}
+	return msg
+}
+
+func putReceipt(putter persistent.Putter, hash common.Hash, receipt *corepb.Receipt) {
+	putProtoMsg(putter, keyReceipt(hash), receipt)
+}
+
+func getAddressTxCount(getter persistent.Getter, addr common.Address) uint64 {
+	enc, _ := getter.Get(keyAddressTxCount(addr))
+	if len(enc) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(enc)
+}
+
+func putAddressTxCount(putter persistent.Putter, addr common.Address, count uint64) {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, count)
+	if err := putter.Put(keyAddressTxCount(addr), buf); err != nil {
+		log.Crit("putAddressTxCount()", err)
+	}
+}
+
+func getAddressTx(getter persistent.Getter, addr common.Address, seq uint64) *AddressTx {
+	enc, _ := getter.Get(keyAddressTx(addr, seq))
+	return decodeAddressTx(enc)
+}
+
+func putAddressTx(putter persistent.Putter, addr common.Address, seq uint64, e *AddressTx) {
+	if err := putter.Put(keyAddressTx(addr, seq), e.encode()); err != nil {
+		log.Crit("putAddressTx()", err)
+	}
+}
+
 func getProtoMsg(getter persistent.Getter, key []byte, message proto.Message) error {
 	enc, err := getter.Get(key)
 	if err != nil {
@@ -191,6 +280,10 @@ func keyChainID() []byte {
 	return []byte(KeyChainID)
 }
 
+func keySchemaVersion() []byte {
+	return []byte(KeySchemaVersion)
+}
+
 func keyLastBlock() []byte {
 	return []byte(KeyLastBlock)
 }
@@ -216,3 +309,33 @@ func keyBlockNum2Hash(num uint64) []byte {
 func keyTx(hash common.Hash) []byte {
 	return append([]byte(KeyPrefixTx), hash[:]...)
 }
+
+func keyTxLookup(hash common.Hash) []byte {
+	return append([]byte(KeyPrefixTxLookup), hash[:]...)
+}
+
+func keyReceipt(hash common.Hash) []byte {
+	return append([]byte(KeyPrefixReceipt), hash[:]...)
+}
+
+func keyAddressIndexed() []byte {
+	return []byte(KeyAddressIndexed)
+}
+
+func keyAddressTxCount(addr common.Address) []byte {
+	return append([]byte(KeyPrefixAddressTxCount), addr[:]...)
+}
+
+func keyAddressTxPrefix(addr common.Address) []byte {
+	return append([]byte(KeyPrefixAddressTx), addr[:]...)
+}
+
+func keyAddressTxSeq(seq uint64) []byte {
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, seq)
+	return buf
+}
+
+func keyAddressTx(addr common.Address, seq uint64) []byte {
+	return append(keyAddressTxPrefix(addr), keyAddressTxSeq(seq)...)
+}
@@ -0,0 +1,92 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"strings"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/persistent"
+)
+
+// named key prefixes of chain db, for inspection tools
+var DBPrefixes = []struct {
+	Name   string
+	Prefix string
+}{
+	{"headers", KeyPrefixHeader},
+	{"bodies", KeyPrefixBody},
+	{"num2hash", KeyPrefixBlockNum2Hash},
+	{"hash2num", KeyPrefixBlockHash2Num},
+	{"txs", KeyPrefixTx},
+	{"txLookups", KeyPrefixTxLookup},
+	{"receipts", KeyPrefixReceipt},
+	{"stateTrie", KeyPrefixStateTrie},
+	{"addrTxCounts", KeyPrefixAddressTxCount},
+	{"addrTxs", KeyPrefixAddressTx},
+}
+
+// DBStat counts keys and bytes of a key prefix in chain db
+type DBStat struct {
+	Name      string
+	Prefix    string
+	Count     uint64
+	KeySize   common.StorageSize
+	ValueSize common.StorageSize
+}
+
+// DBPrefix returns key prefix of name in DBPrefixes, or name itself if not found
+func DBPrefix(name string) string {
+	for _, p := range DBPrefixes {
+		if p.Name == name {
+			return p.Prefix
+		}
+	}
+	return name
+}
+
+// InspectStorage walks all keys of chain db, counting keys and bytes by prefix
+//   keys without known prefix, e.g. chain metadata, counted as "other"
+func InspectStorage(storage persistent.Storage) ([]*DBStat, error) {
+	stats := make([]*DBStat, 0, len(DBPrefixes)+1)
+	for _, p := range DBPrefixes {
+		stats = append(stats, &DBStat{Name: p.Name, Prefix: p.Prefix})
+	}
+	other := &DBStat{Name: "other"}
+	stats = append(stats, other)
+
+	it := storage.Iterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		key := string(it.Key())
+		stat := other
+		for _, s := range stats[:len(DBPrefixes)] {
+			// longest prefix matched
+			if strings.HasPrefix(key, s.Prefix) && (stat == other || len(s.Prefix) > len(stat.Prefix)) {
+				stat = s
+			}
+		}
+		stat.Count++
+		stat.KeySize += common.StorageSize(len(it.Key()))
+		stat.ValueSize += common.StorageSize(len(it.Value()))
+	}
+	if err := it.Error(); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,153 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/log"
+	"github.com/yeeco/gyee/persistent"
+)
+
+// Layout of chain storage is versioned by KeySchemaVersion.
+// Changing layout (key prefixes, encodings) bumps SchemaVersion,
+//   with a migration step upgrading storage of the previous version.
+// Storage created before versioning is version 0.
+
+var (
+	ErrSchemaTooNew  = errors.New("core.chaindb: storage schema newer than supported")
+	ErrSchemaVersion = errors.New("core.chaindb: malformed storage schema version")
+)
+
+// migration upgrades storage from version-1 to version
+type migration struct {
+	version uint32
+	name    string
+	migrate func(storage persistent.Storage) error
+}
+
+// ordered migration steps, migrations[i] upgrades storage to version i+1
+var migrations = []migration{
+	// lookup entries and receipts of sealed txs, not kept before versioning
+	{1, "tx lookup and receipts", backfillTxIndex},
+}
+
+// SchemaVersion of chain storage supported
+func SchemaVersion() uint32 {
+	return uint32(len(migrations))
+}
+
+func getSchemaVersion(getter persistent.Getter) (uint32, error) {
+	enc, err := getter.Get(keySchemaVersion())
+	if err == persistent.ErrKeyNotFound {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(enc) != 4 {
+		return 0, ErrSchemaVersion
+	}
+	return binary.BigEndian.Uint32(enc), nil
+}
+
+func putSchemaVersion(putter persistent.Putter, version uint32) error {
+	enc := make([]byte, 4)
+	binary.BigEndian.PutUint32(enc, version)
+	return putter.Put(keySchemaVersion(), enc)
+}
+
+// upgrade storage to SchemaVersion, running migrations in order
+//   version stored after each step, resuming from it if interrupted
+func migrateStorage(storage persistent.Storage) error {
+	version, err := getSchemaVersion(storage)
+	if err != nil {
+		return err
+	}
+	if version > SchemaVersion() {
+		log.Error("chain storage schema too new", "version", version, "supported", SchemaVersion())
+		return ErrSchemaTooNew
+	}
+	if version == SchemaVersion() {
+		return nil
+	}
+	log.Info("Migrating chain storage", "from", version, "to", SchemaVersion())
+	for _, m := range migrations[version:] {
+		start := time.Now()
+		log.Info("Migration step start", "version", m.version, "name", m.name)
+		if err := m.migrate(storage); err != nil {
+			log.Error("Migration step failed", "version", m.version, "name", m.name, "err", err)
+			return err
+		}
+		if err := putSchemaVersion(storage, m.version); err != nil {
+			return err
+		}
+		log.Info("Migration step done", "version", m.version, "name", m.name,
+			"elapsed", time.Since(start))
+	}
+	return nil
+}
+
+// index txs of canonical blocks stored before versioning, with lookup entries and receipts
+//   txs sealed then were all transfers applied in full, without fee charged
+func backfillTxIndex(storage persistent.Storage) error {
+	if has, err := storage.Has(keyLastBlock()); err != nil || !has {
+		return err
+	}
+	last := getBlockHash2Num(storage, getLastBlock(storage))
+	if last == nil {
+		return ErrBlockNotFound
+	}
+	batch := storage.NewBatch()
+	for n := uint64(0); n <= *last; n++ {
+		hash := getBlockNum2Hash(storage, n)
+		if hash == common.EmptyHash {
+			continue
+		}
+		body := getBlockBody(storage, hash)
+		if body == nil {
+			continue
+		}
+		for i, raw := range body.RawTransactions {
+			tx := new(Transaction)
+			if err := tx.Decode(raw); err != nil {
+				return err
+			}
+			txHash := *tx.Hash()
+			putTxLookup(batch, txHash, &TxLookup{
+				BlockHash: hash,
+				Number:    n,
+				Index:     uint32(i),
+			})
+			receipt := newReceipt(tx, n, i)
+			receipt.fee = new(big.Int)
+			putReceipt(batch, txHash, receipt.ToProto())
+		}
+		if batch.ValueSize() >= persistent.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	return batch.Write()
+}
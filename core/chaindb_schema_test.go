@@ -0,0 +1,131 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/persistent"
+)
+
+func TestSchemaVersion(t *testing.T) {
+	// new storage
+	storage := persistent.NewMemoryStorage()
+	if err := prepareStorage(storage, MainNetID); err != nil {
+		t.Fatalf("prepareStorage() %v", err)
+	}
+	if v, err := getSchemaVersion(storage); err != nil || v != SchemaVersion() {
+		t.Errorf("new storage version %d %v", v, err)
+	}
+
+	// storage before versioning
+	storage = persistent.NewMemoryStorage()
+	if err := prepareStorage(storage, MainNetID); err != nil {
+		t.Fatalf("prepareStorage() %v", err)
+	}
+	storage.Del(keySchemaVersion())
+	if err := prepareStorage(storage, MainNetID); err != nil {
+		t.Fatalf("prepareStorage() unversioned %v", err)
+	}
+	if v, err := getSchemaVersion(storage); err != nil || v != SchemaVersion() {
+		t.Errorf("migrated storage version %d %v", v, err)
+	}
+
+	// storage of newer binary
+	if err := putSchemaVersion(storage, SchemaVersion()+1); err != nil {
+		t.Fatalf("putSchemaVersion() %v", err)
+	}
+	if _, err := NewBlockChain(MainNetID, storage, nil); err != ErrSchemaTooNew {
+		t.Errorf("NewBlockChain() newer schema expect %v, got %v", ErrSchemaTooNew, err)
+	}
+}
+
+func TestSchemaMigrations(t *testing.T) {
+	saved := migrations
+	defer func() { migrations = saved }()
+
+	var steps []uint32
+	failing := errors.New("failing step")
+	fail := true
+	step := func(version uint32) func(persistent.Storage) error {
+		return func(persistent.Storage) error {
+			if version == 3 && fail {
+				return failing
+			}
+			steps = append(steps, version)
+			return nil
+		}
+	}
+	migrations = []migration{{1, "one", step(1)}, {2, "two", step(2)}, {3, "three", step(3)}}
+
+	storage := persistent.NewMemoryStorage()
+	if err := migrateStorage(storage); err != failing {
+		t.Fatalf("migrateStorage() expect %v, got %v", failing, err)
+	}
+	if v, _ := getSchemaVersion(storage); v != 2 || len(steps) != 2 || steps[0] != 1 || steps[1] != 2 {
+		t.Fatalf("interrupted migration version %d steps %v", v, steps)
+	}
+	// resumed from failed step
+	fail = false
+	if err := migrateStorage(storage); err != nil {
+		t.Fatalf("migrateStorage() %v", err)
+	}
+	if v, _ := getSchemaVersion(storage); v != 3 || len(steps) != 3 || steps[2] != 3 {
+		t.Fatalf("resumed migration version %d steps %v", v, steps)
+	}
+}
+
+func TestSchemaBackfillTxIndex(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	storage := persistent.NewMemoryStorage()
+	chain := tv.newChain(t, storage)
+	tv.growChain(t, chain, 3)
+	tx := tv.newTx(t, 0, 1, 1, 1) // sealed in block 2 by growChain
+	hash := *tx.Hash()
+	lookup, receipt := getTxLookup(storage, hash), getReceipt(storage, hash)
+	if lookup == nil || receipt == nil {
+		t.Fatalf("tx of block 2 not indexed")
+	}
+
+	// storage before versioning, without lookup entries and receipts
+	for _, prefix := range []string{KeyPrefixTxLookup, KeyPrefixReceipt} {
+		it := storage.Iterator([]byte(prefix), nil)
+		for it.Next() {
+			storage.Del(common.CopyBytes(it.Key()))
+		}
+		it.Release()
+	}
+	storage.Del(keySchemaVersion())
+
+	reopened, err := NewBlockChain(TestNetID, storage, nil)
+	if err != nil {
+		t.Fatalf("NewBlockChain() %v", err)
+	}
+	if got := getTxLookup(storage, hash); got == nil || *got != *lookup {
+		t.Errorf("backfilled lookup got %v, need %v", got, lookup)
+	}
+	if got := getReceipt(storage, hash); got == nil || !proto.Equal(got, receipt) {
+		t.Errorf("backfilled receipt got %v, need %v", got, receipt)
+	}
+	if got, _ := reopened.GetTransaction(hash); got == nil {
+		t.Errorf("GetTransaction() backfilled tx not found")
+	}
+}
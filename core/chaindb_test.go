@@ -66,3 +66,41 @@ func TestChainDB(t *testing.T) {
 		t.Errorf("getBlockBody() exist got %v", b)
 	}
 }
+
+func TestInspectStorage(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	storage := persistent.NewMemoryStorage()
+	chain := tv.newChain(t, storage)
+	tv.growChain(t, chain, 3)
+
+	stats, err := InspectStorage(storage)
+	if err != nil {
+		t.Fatalf("InspectStorage() %v", err)
+	}
+	counts := make(map[string]uint64)
+	for _, s := range stats {
+		counts[s.Name] = s.Count
+		if s.Count > 0 && (s.KeySize == 0 || s.ValueSize == 0) {
+			t.Errorf("%s size not counted", s.Name)
+		}
+	}
+	for name, count := range map[string]uint64{
+		"headers":   4,
+		"bodies":    4,
+		"num2hash":  4,
+		"txs":       3,
+		"txLookups": 3,
+		"receipts":  3,
+		"other":     3, // chain id, schema version, last block
+	} {
+		if counts[name] != count {
+			t.Errorf("%s count %d, need %d", name, counts[name], count)
+		}
+	}
+	if counts["stateTrie"] == 0 {
+		t.Errorf("stateTrie not counted")
+	}
+	if DBPrefix("headers") != KeyPrefixHeader || DBPrefix("blkB-") != KeyPrefixBody {
+		t.Errorf("DBPrefix() mismatch")
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/core/yvm"
+	"github.com/yeeco/gyee/persistent"
+)
+
+func (tv *testValidators) signTx(t *testing.T, i int, tx *Transaction) *Transaction {
+	if err := tx.Sign(tv.signers[i]); err != nil {
+		t.Fatalf("tx.Sign() %v", err)
+	}
+	if err := tx.VerifySig(); err != nil {
+		t.Fatalf("tx.VerifySig() %v", err)
+	}
+	return tx
+}
+
+func TestBlockChainContractTx(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	chainID := uint32(TestNetID)
+
+	// storage[0] += input word 0
+	code := []byte{
+		byte(yvm.PUSH), 1, 0, byte(yvm.SLOAD),
+		byte(yvm.PUSH), 1, 0, byte(yvm.CALLDATALOAD),
+		byte(yvm.ADD),
+		byte(yvm.PUSH), 1, 0, byte(yvm.SSTORE),
+	}
+	contract, err := yvm.ContractAddress(chainID, tv.addrs[0], 0)
+	if err != nil {
+		t.Fatalf("ContractAddress() %v", err)
+	}
+	input := common.BytesToHash([]byte{7})
+	fee := big.NewInt(100000 * GasPrice)
+	txs := Transactions{
+		tv.signTx(t, 0, NewDeployTransaction(chainID, 0, code, big.NewInt(10), fee, 100000)),
+		tv.signTx(t, 0, NewCallTransaction(chainID, 1, &contract, input[:], big.NewInt(1), fee, 100000)),
+		// out of gas, fee charged, value kept
+		tv.signTx(t, 0, NewCallTransaction(chainID, 2, &contract, input[:], big.NewInt(1), fee, 10)),
+	}
+	// fee not covering gas limit, not sealed
+	underpaid := tv.signTx(t, 0, NewCallTransaction(chainID, 3, &contract, input[:], big.NewInt(1), fee, 100001))
+	b := tv.nextBlock(t, chain, chain.LastBlock(), append(txs, underpaid))
+	if err := chain.AddBlock(b); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+	if len(b.transactions) != len(txs) || chain.GetReceipt(*underpaid.Hash()) != nil {
+		t.Errorf("tx of fee not covering gas sealed")
+	}
+
+	deployed := chain.GetReceipt(*txs[0].Hash())
+	if deployed == nil || !deployed.Succeeded() || deployed.ContractAddress() == nil ||
+		*deployed.ContractAddress() != contract || deployed.GasUsed() == 0 {
+		t.Fatalf("deploy receipt %v", deployed)
+	}
+	if called := chain.GetReceipt(*txs[1].Hash()); called == nil || !called.Succeeded() {
+		t.Errorf("call receipt %v", called)
+	}
+	failed := chain.GetReceipt(*txs[2].Hash())
+	if failed == nil || failed.Succeeded() || failed.FailReason() != ReceiptFailContract ||
+		failed.Fee().Cmp(fee) != 0 {
+		t.Errorf("failed call receipt %v", failed)
+	}
+
+	st, err := chain.State()
+	if err != nil {
+		t.Fatalf("State() %v", err)
+	}
+	account := st.GetAccount(contract, false)
+	if account == nil {
+		t.Fatalf("contract account missing")
+	}
+	if got := account.GetStorage(common.Hash{}); got != input {
+		t.Errorf("contract storage got %x", got)
+	}
+	if got := account.Balance().Int64(); got != 11 {
+		t.Errorf("contract balance got %d", got)
+	}
+	if got := st.GetAccount(tv.addrs[0], false).Nonce(); got != 3 {
+		t.Errorf("sender nonce got %d", got)
+	}
+}
@@ -43,6 +43,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"io/ioutil"
+	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -66,6 +67,17 @@ var (
 	ErrNoCoinbase          = errors.New("coinbase not provided")
 	ErrNoCoinbasePwdFile   = errors.New("coinbase keystore password file not provided")
 	ErrCoinbaseKeyNotFound = errors.New("coinbase not found in keystore")
+	ErrDBEngineUnknown     = errors.New("unknown chain db engine")
+	ErrDBEngineMismatch    = errors.New("chain db created by another engine")
+)
+
+// key-value engines of chain db, selected by ChainConfig.DBEngine
+const (
+	DBEngineLevelDB = "leveldb" // default
+	DBEngineBoltDB  = "boltdb"
+
+	chainDataBoltFile = "chain.bolt" // bolt db file in chain data dir
+	chainDataLevelDB  = "CURRENT"    // present in leveldb dir
 )
 
 type Core struct {
@@ -77,8 +89,10 @@ type Core struct {
 	blockChain *BlockChain
 	blockPool  *BlockPool
 	txPool     *TransactionPool
+	downloader *Downloader
 
 	yvm        yvm.YVM
+	feed       *EventFeed
 	subscriber *p2p.Subscriber
 	subsChan   chan p2p.Message
 
@@ -101,8 +115,7 @@ func NewCoreWithGenesis(node INode, conf *config.Config, genesis *Genesis) (*Cor
 	log.Info("Create new core")
 
 	// prepare chain db
-	dbPath := filepath.Join(conf.NodeDir, "chaindata")
-	storage, err := persistent.NewLevelStorage(dbPath)
+	storage, err := OpenChainStorage(conf)
 	if err != nil {
 		return nil, err
 	}
@@ -120,6 +133,8 @@ func NewCoreWithGenesis(node INode, conf *config.Config, genesis *Genesis) (*Cor
 		node:    node,
 		config:  conf,
 		storage: storage,
+		yvm:     yvm.NewYVM(),
+		feed:    NewEventFeed(),
 		quitCh:  make(chan struct{}),
 	}
 	core.blockChain, err = NewBlockChainWithCore(core)
@@ -134,6 +149,10 @@ func NewCoreWithGenesis(node INode, conf *config.Config, genesis *Genesis) (*Cor
 	if err != nil {
 		return nil, err
 	}
+	core.downloader, err = NewDownloader(core)
+	if err != nil {
+		return nil, err
+	}
 	if conf.Chain.Mine {
 		if err := core.prepareCoinbase(); err != nil {
 			return nil, err
@@ -143,6 +162,46 @@ func NewCoreWithGenesis(node INode, conf *config.Config, genesis *Genesis) (*Cor
 	return core, nil
 }
 
+// path of chain db in node dir
+func ChainDataDir(conf *config.Config) string {
+	return filepath.Join(conf.NodeDir, "chaindata")
+}
+
+// OpenChainStorage opens chain db in node dir, with configured engine
+//   refusing chain data dir created by another engine
+func OpenChainStorage(conf *config.Config) (persistent.Storage, error) {
+	dir := ChainDataDir(conf)
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	switch conf.Chain.DBEngine {
+	case "", DBEngineLevelDB:
+		if exists(chainDataBoltFile) {
+			return nil, ErrDBEngineMismatch
+		}
+		storage, err := persistent.NewLevelStorage(dir)
+		if err != nil {
+			return nil, err
+		}
+		return storage, nil
+	case DBEngineBoltDB:
+		if exists(chainDataLevelDB) {
+			return nil, ErrDBEngineMismatch
+		}
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return nil, err
+		}
+		storage, err := persistent.NewBoltStorage(filepath.Join(dir, chainDataBoltFile))
+		if err != nil {
+			return nil, err
+		}
+		return storage, nil
+	default:
+		return nil, ErrDBEngineUnknown
+	}
+}
+
 func (c *Core) Start() error {
 	c.lock.Lock()
 	defer c.lock.Unlock()
@@ -154,6 +213,10 @@ func (c *Core) Start() error {
 
 	c.blockPool.Start()
 	c.txPool.Start()
+	c.downloader.Start()
+
+	// catch up with peers before joining consensus
+	c.downloader.Trigger()
 
 	//如果开启挖矿
 	if c.config.Chain.Mine {
@@ -192,6 +255,9 @@ func (c *Core) Stop() error {
 	// unsubscribe from p2p net
 	c.node.P2pService().UnRegister(c.subscriber)
 
+	// stop downloader and wait
+	c.downloader.Stop()
+
 	// stop tx pool and wait
 	c.txPool.Stop()
 
@@ -288,8 +354,10 @@ func (c *Core) handleEngineOutput(o *consensus.Output) {
 	}
 	if currentHeight+TooFarBlocks < o.H {
 		log.Warn("engine height too high", "engineH", o.H, "chainH", currentHeight)
-		// TODO: block chain too far behind, should reSync
+		// block chain too far behind, reSync with peers
+		c.downloader.Trigger()
 	}
+	c.feed.Send(&Event{Type: EventSealedTxs, Sealed: &SealedTxs{Height: o.H, Time: o.T, Txs: o.Txs}})
 	go func() {
 		c.wg.Add(1)
 		defer c.wg.Done()
@@ -370,6 +438,19 @@ func (c *Core) Chain() *BlockChain {
 	return c.blockChain
 }
 
+func (c *Core) TxPool() *TransactionPool {
+	return c.txPool
+}
+
+// EventFeed publishes new heads, txs accepted by pool and txs output by engine
+func (c *Core) EventFeed() *EventFeed {
+	return c.feed
+}
+
+func (c *Core) Downloader() *Downloader {
+	return c.downloader
+}
+
 func (c *Core) MinerAddr() *address.Address {
 	return c.minerAddr.Copy()
 }
@@ -19,3 +19,48 @@
  */
 
 package core
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/yeeco/gyee/config"
+)
+
+func TestOpenChainStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gyee-core")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	conf := &config.Config{NodeDir: dir, Chain: &config.ChainConfig{DBEngine: DBEngineBoltDB}}
+
+	storage, err := OpenChainStorage(conf)
+	if err != nil {
+		t.Fatalf("OpenChainStorage() boltdb %v", err)
+	}
+	storage.Put([]byte("key"), []byte("value"))
+	storage.Close()
+
+	// reopened with same engine
+	storage, err = OpenChainStorage(conf)
+	if err != nil {
+		t.Fatalf("OpenChainStorage() reopen %v", err)
+	}
+	if v, err := storage.Get([]byte("key")); err != nil || string(v) != "value" {
+		t.Errorf("reopened storage got %q %v", v, err)
+	}
+	storage.Close()
+
+	for engine, expected := range map[string]error{
+		"":              ErrDBEngineMismatch,
+		DBEngineLevelDB: ErrDBEngineMismatch,
+		"unknown":       ErrDBEngineUnknown,
+	} {
+		conf.Chain.DBEngine = engine
+		if _, err := OpenChainStorage(conf); err != expected {
+			t.Errorf("OpenChainStorage() %q expect %v, got %v", engine, expected, err)
+		}
+	}
+}
@@ -0,0 +1,409 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+/*
+ 同步落后的区块
+ 向peer询问最新区块头
+ 按范围拉取区块头，校验链接关系
+ 拉取区块体，验证签名后按顺序加入链
+ 空链可从可信区块同步状态，按哈希拉取状态树节点
+*/
+
+package core
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/common/trie"
+	"github.com/yeeco/gyee/core/pb"
+	"github.com/yeeco/gyee/core/state"
+	sha3 "github.com/yeeco/gyee/crypto/hash"
+	"github.com/yeeco/gyee/log"
+)
+
+const (
+	MaxTrieNodeFetch = 256 // max state trie nodes requested in a batch during state sync
+	MaxSyncRetries   = 3   // retries of a request for data peer advertised
+	MaxBadHeads      = 64  // heads advertised but not served, remembered to ignore
+
+	// min milliseconds between blocks, bounding height of head claimed by peer
+	MinBlockInterval = 100
+)
+
+var (
+	ErrSyncBusy           = errors.New("core.sync: already syncing")
+	ErrSyncCancelled      = errors.New("core.sync: cancelled")
+	ErrSyncNoPeerData     = errors.New("core.sync: no chain data from peer")
+	ErrSyncHeaderMismatch = errors.New("core.sync: header chain mismatch")
+	ErrSyncSignature      = errors.New("core.sync: not enough block signatures")
+	ErrSyncCheckpoint     = errors.New("core.sync: checkpoint block mismatch")
+	ErrSyncTrieNode       = errors.New("core.sync: trie node hash mismatch")
+	ErrSyncHeadTooHigh    = errors.New("core.sync: peer head above expected height")
+	ErrSyncBadHead        = errors.New("core.sync: peer head not served")
+)
+
+// chainInfoGetter asks peers for chain data, implemented by p2p.Service
+type chainInfoGetter interface {
+	GetChainInfo(kind string, key []byte) ([]byte, error)
+}
+
+// Downloader catches up a lagging chain with peers
+//   fetches peer last block header, header ranges and bodies with chainInfoGetter
+//   verifies and imports blocks in order
+//   empty chain with a checkpoint syncs state of checkpoint block first
+//   head claimed by peer is bounded by time elapsed since local head,
+//   and ignored once peers failed to serve blocks up to it
+type Downloader struct {
+	core       *Core
+	chain      *BlockChain
+	peer       chainInfoGetter
+	checkpoint common.Hash
+	badHeads   map[common.Hash]struct{}
+
+	syncing int32
+	syncCh  chan struct{}
+
+	quitCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+func NewDownloader(core *Core) (*Downloader, error) {
+	log.Info("Create New Downloader")
+	d := newDownloader(core.blockChain, nil)
+	d.core = core
+	if checkpoint := core.config.Chain.Checkpoint; len(checkpoint) > 0 {
+		d.checkpoint = common.HexToHash(checkpoint)
+	}
+	return d, nil
+}
+
+func newDownloader(chain *BlockChain, peer chainInfoGetter) *Downloader {
+	return &Downloader{
+		chain:    chain,
+		peer:     peer,
+		badHeads: make(map[common.Hash]struct{}),
+		syncCh:   make(chan struct{}, 1),
+		quitCh:   make(chan struct{}),
+	}
+}
+
+func (d *Downloader) Start() {
+	log.Info("Downloader Start...")
+	if d.peer == nil {
+		d.peer = d.core.node.P2pService()
+	}
+
+	d.wg.Add(1)
+	go d.loop()
+}
+
+func (d *Downloader) Stop() {
+	log.Info("Downloader Stop...")
+
+	close(d.quitCh)
+	d.wg.Wait()
+}
+
+// Trigger a chain sync in background, ignored if one is already pending
+func (d *Downloader) Trigger() {
+	select {
+	case d.syncCh <- struct{}{}:
+	default:
+	}
+}
+
+func (d *Downloader) Syncing() bool {
+	return atomic.LoadInt32(&d.syncing) != 0
+}
+
+func (d *Downloader) loop() {
+	defer d.wg.Done()
+	log.Trace("Downloader loop...")
+
+	for {
+		select {
+		case <-d.quitCh:
+			log.Info("Downloader loop end.")
+			return
+		case <-d.syncCh:
+			if d.checkpoint != common.EmptyHash && d.chain.CurrentBlockHeight() == 0 {
+				if err := d.SyncState(d.checkpoint); err != nil {
+					log.Warn("state sync failed", "checkpoint", d.checkpoint, "err", err)
+					continue
+				}
+			}
+			switch err := d.Synchronise(); err {
+			case nil:
+			case ErrSyncNoPeerData, ErrSyncBadHead:
+				log.Debug("chain sync skipped", "err", err)
+			default:
+				log.Warn("chain sync failed", "err", err)
+			}
+		}
+	}
+}
+
+// Synchronise chain with peer head, blocking till done or failed
+func (d *Downloader) Synchronise() error {
+	if !atomic.CompareAndSwapInt32(&d.syncing, 0, 1) {
+		return ErrSyncBusy
+	}
+	defer atomic.StoreInt32(&d.syncing, 0)
+
+	head, err := d.fetchLastHeader()
+	if err != nil {
+		return err
+	}
+	enc, err := head.Hash()
+	if err != nil {
+		return err
+	}
+	headHash := common.BytesToHash(enc)
+	if _, ok := d.badHeads[headHash]; ok {
+		return ErrSyncBadHead
+	}
+	local := d.chain.CurrentBlockHeight()
+	if head.Number <= local {
+		return nil
+	}
+	if expected := d.expectedHeight(time.Now()); head.Number > expected {
+		log.Warn("sync head too high", "remote", head.Number, "expected", expected)
+		d.markBadHead(headHash)
+		return ErrSyncHeadTooHigh
+	}
+	log.Info("chain sync start", "local", local, "remote", head.Number)
+
+	parent := d.chain.LastBlock()
+	for parent.Number() < head.Number {
+		select {
+		case <-d.quitCh:
+			return ErrSyncCancelled
+		default:
+		}
+		from := parent.Number() + 1
+		count := head.Number - parent.Number()
+		if count > MaxHeaderFetch {
+			count = MaxHeaderFetch
+		}
+		headers, err := d.fetchHeadersRetry(from, uint32(count))
+		if err == ErrSyncNoPeerData {
+			log.Warn("sync head not served", "remote", head.Number, "missing", from)
+			d.markBadHead(headHash)
+			return ErrSyncBadHead
+		}
+		if err != nil {
+			return err
+		}
+		for _, pbHeader := range headers {
+			b, err := d.fetchBlock(pbHeader)
+			if err != nil {
+				return err
+			}
+			if b.Number() != parent.Number()+1 || b.ParentHash() != parent.Hash() {
+				log.Warn("sync header not linked", "parent", parent.Number(), "block", b.Number())
+				return ErrSyncHeaderMismatch
+			}
+			if err := d.chain.importBlock(b); err != nil {
+				return err
+			}
+			parent = b
+		}
+	}
+	log.Info("chain sync done", "height", parent.Number(), "hash", parent.Hash())
+	return nil
+}
+
+// SyncState downloads state of trusted block by hash, and makes it last block
+//   account, storage, code and consensus trie nodes fetched by hash from peer
+//   blocks following it can be synchronised as usual
+func (d *Downloader) SyncState(trusted common.Hash) error {
+	if !atomic.CompareAndSwapInt32(&d.syncing, 0, 1) {
+		return ErrSyncBusy
+	}
+	defer atomic.StoreInt32(&d.syncing, 0)
+
+	enc, err := d.peer.GetChainInfo(ChainDataHeader, trusted[:])
+	if err != nil {
+		return err
+	}
+	if len(enc) == 0 {
+		return ErrSyncNoPeerData
+	}
+	pbHeader := new(corepb.SignedBlockHeader)
+	if err := proto.Unmarshal(enc, pbHeader); err != nil {
+		return err
+	}
+	b, err := d.fetchBlock(pbHeader)
+	if err != nil {
+		return err
+	}
+	if b.Hash() != trusted {
+		return ErrSyncCheckpoint
+	}
+	// trusted by hash, may be far above local head
+	if ChainID(b.ChainID()) != d.chain.chainID {
+		return ErrBlockChainID
+	}
+	if err := b.VerifyBody(); err != nil {
+		return err
+	}
+	if b.Number() <= d.chain.CurrentBlockHeight() {
+		return nil
+	}
+	log.Info("state sync start", "number", b.Number(), "hash", trusted)
+
+	diskdb := d.chain.stateDB.TrieDB().DiskDB()
+	sched := state.NewStateSync(b.StateRoot(), diskdb)
+	sched.AddSubTrie(b.ConsensusRoot(), 0, common.Hash{}, nil)
+	nodes := 0
+	for sched.Pending() > 0 {
+		select {
+		case <-d.quitCh:
+			return ErrSyncCancelled
+		default:
+		}
+		hashes := sched.Missing(MaxTrieNodeFetch)
+		if len(hashes) == 0 {
+			break
+		}
+		results := make([]trie.SyncResult, 0, len(hashes))
+		for _, hash := range hashes {
+			data, err := d.peer.GetChainInfo(ChainDataTrieNode, hash[:])
+			if err != nil {
+				return err
+			}
+			if len(data) == 0 {
+				return ErrSyncNoPeerData
+			}
+			if common.BytesToHash(sha3.Sha3256(data)) != hash {
+				log.Warn("state sync node mismatch", "hash", hash)
+				return ErrSyncTrieNode
+			}
+			results = append(results, trie.SyncResult{Hash: hash, Data: data})
+		}
+		if _, index, err := sched.Process(results); err != nil {
+			log.Warn("state sync node process fails", "hash", results[index].Hash, "err", err)
+			return err
+		}
+		batch := diskdb.NewBatch()
+		if _, err := sched.Commit(batch); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		nodes += len(results)
+	}
+	if err := d.chain.insertSyncedBlock(b); err != nil {
+		return err
+	}
+	log.Info("state sync done", "number", b.Number(), "hash", trusted, "nodes", nodes)
+	return nil
+}
+
+// highest block number expected by now, if blocks produced at MinBlockInterval since local head
+func (d *Downloader) expectedHeight(now time.Time) uint64 {
+	last := d.chain.LastBlock()
+	nowMs := uint64(now.UnixNano() / int64(time.Millisecond))
+	if nowMs <= last.Time() {
+		return last.Number() + TooFarBlocks
+	}
+	return last.Number() + TooFarBlocks + (nowMs-last.Time())/MinBlockInterval
+}
+
+// remember a head peers failed to serve, so claims of it are ignored
+func (d *Downloader) markBadHead(hash common.Hash) {
+	if len(d.badHeads) >= MaxBadHeads {
+		d.badHeads = make(map[common.Hash]struct{})
+	}
+	d.badHeads[hash] = struct{}{}
+}
+
+func (d *Downloader) fetchLastHeader() (*BlockHeader, error) {
+	enc, err := d.peer.GetChainInfo(ChainDataLastBlock, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(enc) == 0 {
+		return nil, ErrSyncNoPeerData
+	}
+	pbHeader := new(corepb.SignedBlockHeader)
+	if err := proto.Unmarshal(enc, pbHeader); err != nil {
+		return nil, err
+	}
+	header := new(BlockHeader)
+	if err := rlp.DecodeBytes(pbHeader.Header, header); err != nil {
+		return nil, err
+	}
+	if ChainID(header.ChainID) != d.chain.chainID {
+		return nil, ErrBlockChainID
+	}
+	return header, nil
+}
+
+func (d *Downloader) fetchHeaders(from uint64, count uint32) ([]*corepb.SignedBlockHeader, error) {
+	enc, err := d.peer.GetChainInfo(ChainDataHeaders, chainDataHeadersKey(from, count))
+	if err != nil {
+		return nil, err
+	}
+	msg := new(corepb.SignedBlockHeaders)
+	if err := proto.Unmarshal(enc, msg); err != nil {
+		return nil, err
+	}
+	if len(msg.Headers) == 0 {
+		return nil, ErrSyncNoPeerData
+	}
+	return msg.Headers, nil
+}
+
+// fetch headers advertised by peer, retried as request may be served by another peer
+func (d *Downloader) fetchHeadersRetry(from uint64, count uint32) ([]*corepb.SignedBlockHeader, error) {
+	for i := 0; ; i++ {
+		headers, err := d.fetchHeaders(from, count)
+		if err != ErrSyncNoPeerData || i >= MaxSyncRetries {
+			return headers, err
+		}
+		log.Debug("sync headers retry", "from", from, "count", count, "retry", i+1)
+	}
+}
+
+// fetch body for header, and assemble them as block
+func (d *Downloader) fetchBlock(pbHeader *corepb.SignedBlockHeader) (*Block, error) {
+	b := new(Block)
+	if err := b.setProto(pbHeader, nil); err != nil {
+		return nil, err
+	}
+	hash := b.Hash()
+	enc, err := d.peer.GetChainInfo(ChainDataBody, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	body := new(corepb.BlockBody)
+	if err := proto.Unmarshal(enc, body); err != nil {
+		return nil, err
+	}
+	if err := b.setProto(pbHeader, body); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
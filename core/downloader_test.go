@@ -0,0 +1,265 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/core/yvm"
+	"github.com/yeeco/gyee/persistent"
+)
+
+// peer serving chain data directly from a local chain
+type testChainPeer struct {
+	chain *BlockChain
+}
+
+func (p *testChainPeer) GetChainInfo(kind string, key []byte) ([]byte, error) {
+	return p.chain.GetChainData(kind, key), nil
+}
+
+func TestDownloaderSync(t *testing.T) {
+	tv := newTestValidators(t, 4)
+	src := tv.newChain(t, persistent.NewMemoryStorage())
+	tv.growChain(t, src, MaxHeaderFetch*2+10)
+
+	dst := tv.newChain(t, persistent.NewMemoryStorage())
+	d := newDownloader(dst, &testChainPeer{chain: src})
+	if err := d.Synchronise(); err != nil {
+		t.Fatalf("Synchronise() %v", err)
+	}
+	if dst.CurrentBlockHeight() != src.CurrentBlockHeight() {
+		t.Fatalf("height mismatch, need %d got %d",
+			src.CurrentBlockHeight(), dst.CurrentBlockHeight())
+	}
+	if dst.LastBlock().Hash() != src.LastBlock().Hash() {
+		t.Fatalf("last block mismatch")
+	}
+	if dst.LastBlock().StateRoot() != src.LastBlock().StateRoot() {
+		t.Fatalf("state root mismatch")
+	}
+	// synced again with nothing new
+	if err := d.Synchronise(); err != nil {
+		t.Fatalf("Synchronise() again %v", err)
+	}
+}
+
+func TestDownloaderRejectsUnsigned(t *testing.T) {
+	tv := newTestValidators(t, 4)
+	src := tv.newChain(t, persistent.NewMemoryStorage())
+	// block signed by a single validator out of 4
+	last := src.LastBlock()
+	b, err := src.BuildNextBlock(last, last.Time()+1, nil)
+	if err != nil {
+		t.Fatalf("BuildNextBlock() %v", err)
+	}
+	if err := b.Sign(tv.signers[0]); err != nil {
+		t.Fatalf("block.Sign() %v", err)
+	}
+	if err := src.AddBlock(b); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+
+	dst := tv.newChain(t, persistent.NewMemoryStorage())
+	d := newDownloader(dst, &testChainPeer{chain: src})
+	if err := d.Synchronise(); err != ErrSyncSignature {
+		t.Fatalf("Synchronise() expect %v, got %v", ErrSyncSignature, err)
+	}
+	if dst.CurrentBlockHeight() != 0 {
+		t.Fatalf("unsigned block imported")
+	}
+}
+
+// peer claiming a forged head, counting header requests
+type testLyingPeer struct {
+	testChainPeer
+	head     *BlockHeader
+	requests int
+}
+
+func (p *testLyingPeer) GetChainInfo(kind string, key []byte) ([]byte, error) {
+	switch kind {
+	case ChainDataLastBlock:
+		pbHeader, err := p.head.toSignedProto()
+		if err != nil {
+			return nil, err
+		}
+		return proto.Marshal(pbHeader)
+	case ChainDataHeaders:
+		p.requests++
+	}
+	return p.testChainPeer.GetChainInfo(kind, key)
+}
+
+func TestDownloaderBadHead(t *testing.T) {
+	tv := newTestValidators(t, 4)
+	src := tv.newChain(t, persistent.NewMemoryStorage())
+	tv.growChain(t, src, 2)
+
+	// head far above time elapsed since local head
+	head := CopyHeader(src.LastBlock().header)
+	head.Number = 1 << 60
+	dst := tv.newChain(t, persistent.NewMemoryStorage())
+	peer := &testLyingPeer{testChainPeer: testChainPeer{chain: src}, head: head}
+	d := newDownloader(dst, peer)
+	if err := d.Synchronise(); err != ErrSyncHeadTooHigh {
+		t.Fatalf("Synchronise() expect %v, got %v", ErrSyncHeadTooHigh, err)
+	}
+	if peer.requests != 0 {
+		t.Fatalf("headers requested for head too high")
+	}
+
+	// head advertised, but blocks up to it not served
+	head = CopyHeader(head)
+	head.Number = 5
+	peer.head = head
+	if err := d.Synchronise(); err != ErrSyncBadHead {
+		t.Fatalf("Synchronise() expect %v, got %v", ErrSyncBadHead, err)
+	}
+	if dst.LastBlock().Hash() != src.LastBlock().Hash() {
+		t.Fatalf("served blocks not imported, got %d", dst.CurrentBlockHeight())
+	}
+	if peer.requests != 2+MaxSyncRetries {
+		t.Fatalf("header requests, need %d got %d", 2+MaxSyncRetries, peer.requests)
+	}
+	// claim of the same head ignored
+	if err := d.Synchronise(); err != ErrSyncBadHead || peer.requests != 2+MaxSyncRetries {
+		t.Fatalf("Synchronise() bad head again, got %v with %d requests", err, peer.requests)
+	}
+}
+
+// peer serving trie nodes altered
+type testCorruptPeer struct {
+	testChainPeer
+}
+
+func (p *testCorruptPeer) GetChainInfo(kind string, key []byte) ([]byte, error) {
+	data, err := p.testChainPeer.GetChainInfo(kind, key)
+	if kind == ChainDataTrieNode && len(data) > 0 {
+		data = common.CopyBytes(data)
+		data[len(data)-1] ^= 0xff
+	}
+	return data, err
+}
+
+func TestDownloaderSyncState(t *testing.T) {
+	tv := newTestValidators(t, 4)
+	src := tv.newChain(t, persistent.NewMemoryStorage())
+	tv.growChain(t, src, 2)
+
+	// contract with storage, storage[0] = input word 0
+	code := []byte{
+		byte(yvm.PUSH), 1, 0, byte(yvm.CALLDATALOAD),
+		byte(yvm.PUSH), 1, 0, byte(yvm.SSTORE),
+	}
+	chainID := uint32(TestNetID)
+	contract, err := yvm.ContractAddress(chainID, tv.addrs[2], 0)
+	if err != nil {
+		t.Fatalf("ContractAddress() %v", err)
+	}
+	input := common.BytesToHash([]byte{9})
+	fee := big.NewInt(100000 * GasPrice)
+	b := tv.nextBlock(t, src, src.LastBlock(), Transactions{
+		tv.newTx(t, 0, 1, 2, 1),
+		tv.signTx(t, 2, NewDeployTransaction(chainID, 0, code, new(big.Int), fee, 100000)),
+		tv.signTx(t, 2, NewCallTransaction(chainID, 1, &contract, input[:], new(big.Int), fee, 100000)),
+	})
+	if err := src.AddBlock(b); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+	checkpoint := src.LastBlock()
+	tv.growChain(t, src, 3)
+
+	// corrupted trie nodes rejected
+	bad := tv.newChain(t, persistent.NewMemoryStorage())
+	d := newDownloader(bad, &testCorruptPeer{testChainPeer{chain: src}})
+	if err := d.SyncState(checkpoint.Hash()); err != ErrSyncTrieNode {
+		t.Fatalf("SyncState() corrupted expect %v, got %v", ErrSyncTrieNode, err)
+	}
+	if bad.CurrentBlockHeight() != 0 {
+		t.Fatalf("chain head moved on failed state sync")
+	}
+
+	dst := tv.newChain(t, persistent.NewMemoryStorage())
+	d = newDownloader(dst, &testChainPeer{chain: src})
+	if err := d.SyncState(common.Hash{1}); err != ErrSyncNoPeerData {
+		t.Fatalf("SyncState() unknown block expect %v, got %v", ErrSyncNoPeerData, err)
+	}
+	if err := d.SyncState(checkpoint.Hash()); err != nil {
+		t.Fatalf("SyncState() %v", err)
+	}
+	if dst.LastBlock().Hash() != checkpoint.Hash() {
+		t.Fatalf("last block not checkpoint, got %d", dst.CurrentBlockHeight())
+	}
+	st, err := dst.State()
+	if err != nil {
+		t.Fatalf("State() %v", err)
+	}
+	account := st.GetAccount(contract, false)
+	if account == nil {
+		t.Fatalf("contract account missing")
+	}
+	if got := account.GetStorage(common.Hash{}); got != input {
+		t.Errorf("contract storage got %x", got)
+	}
+	if len(account.Code()) != len(code) {
+		t.Errorf("contract code got %x", account.Code())
+	}
+	srcState, err := src.StateAt(checkpoint.StateRoot())
+	if err != nil {
+		t.Fatalf("StateAt() %v", err)
+	}
+	for _, addr := range tv.addrs {
+		if got, need := st.GetAccount(addr, false).Balance(), srcState.GetAccount(addr, false).Balance(); got.Cmp(need) != 0 {
+			t.Errorf("balance of %x got %v, need %v", addr, got, need)
+		}
+	}
+
+	// blocks after checkpoint replayed on synced state
+	if err := d.Synchronise(); err != nil {
+		t.Fatalf("Synchronise() %v", err)
+	}
+	if dst.LastBlock().Hash() != src.LastBlock().Hash() {
+		t.Fatalf("last block mismatch, got %d", dst.CurrentBlockHeight())
+	}
+}
+
+func TestDownloaderSyncStateFarCheckpoint(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	src := tv.newChain(t, persistent.NewMemoryStorage())
+	tv.growChain(t, src, TooFarBlocks+5)
+	checkpoint := src.GetBlockByNumber(TooFarBlocks + 2)
+
+	dst := tv.newChain(t, persistent.NewMemoryStorage())
+	d := newDownloader(dst, &testChainPeer{chain: src})
+	if err := d.SyncState(checkpoint.Hash()); err != nil {
+		t.Fatalf("SyncState() %v", err)
+	}
+	if dst.LastBlock().Hash() != checkpoint.Hash() {
+		t.Fatalf("last block not checkpoint, got %d", dst.CurrentBlockHeight())
+	}
+	if err := d.Synchronise(); err != nil {
+		t.Fatalf("Synchronise() %v", err)
+	}
+	if dst.LastBlock().Hash() != src.LastBlock().Hash() {
+		t.Fatalf("last block mismatch, got %d", dst.CurrentBlockHeight())
+	}
+}
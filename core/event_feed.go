@@ -0,0 +1,159 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/log"
+)
+
+var (
+	ErrSubscriptionSlow = errors.New("subscriber too slow, dropped")
+)
+
+// EventType of chain events published to EventFeed
+type EventType int
+
+const (
+	EventNewHead      EventType = iota // block added as last block, Event.Block
+	EventNewPendingTx                  // tx accepted into tx pool, Event.Tx
+	EventSealedTxs                     // txs output by consensus engine to be sealed, Event.Sealed
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventNewHead:
+		return "newHead"
+	case EventNewPendingTx:
+		return "newPendingTx"
+	case EventSealedTxs:
+		return "sealedTxs"
+	default:
+		return "unknown"
+	}
+}
+
+// txs of a height output by consensus engine
+type SealedTxs struct {
+	Height uint64
+	Time   time.Time
+	Txs    []common.Hash
+}
+
+type Event struct {
+	Type   EventType
+	Block  *Block
+	Tx     *Transaction
+	Sealed *SealedTxs
+}
+
+// EventFeed delivers chain events to subscribers
+//   each subscriber has its own buffer, a subscriber with buffer full is dropped
+//   instead of blocking the publisher
+type EventFeed struct {
+	mu   sync.Mutex
+	subs map[*Subscription]struct{}
+}
+
+func NewEventFeed() *EventFeed {
+	return &EventFeed{subs: make(map[*Subscription]struct{})}
+}
+
+// Subscription receives events of subscribed types from feed
+type Subscription struct {
+	feed  *EventFeed
+	types map[EventType]bool
+	ch    chan *Event
+	err   error
+}
+
+// Subscribe events of types, with buffer of events not received yet
+func (f *EventFeed) Subscribe(buffer int, types ...EventType) *Subscription {
+	sub := &Subscription{
+		feed:  f,
+		types: make(map[EventType]bool, len(types)),
+		ch:    make(chan *Event, buffer),
+	}
+	for _, t := range types {
+		sub.types[t] = true
+	}
+	f.mu.Lock()
+	f.subs[sub] = struct{}{}
+	f.mu.Unlock()
+	return sub
+}
+
+// Send delivers event to subscribers without blocking, safe on nil feed
+func (f *EventFeed) Send(ev *Event) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for sub := range f.subs {
+		if !sub.types[ev.Type] {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			log.Warn("event subscriber dropped", "type", ev.Type, "buffer", cap(sub.ch))
+			sub.close(ErrSubscriptionSlow)
+		}
+	}
+}
+
+// number of subscribers
+func (f *EventFeed) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subs)
+}
+
+// with feed lock held
+func (sub *Subscription) close(err error) {
+	if _, ok := sub.feed.subs[sub]; !ok {
+		return
+	}
+	delete(sub.feed.subs, sub)
+	sub.err = err
+	close(sub.ch)
+}
+
+// Chan of events, closed when unsubscribed or dropped
+func (sub *Subscription) Chan() <-chan *Event {
+	return sub.ch
+}
+
+// Err tells why subscription closed, ErrSubscriptionSlow if dropped
+//   nil if still subscribed or unsubscribed
+func (sub *Subscription) Err() error {
+	sub.feed.mu.Lock()
+	defer sub.feed.mu.Unlock()
+	return sub.err
+}
+
+func (sub *Subscription) Unsubscribe() {
+	sub.feed.mu.Lock()
+	defer sub.feed.mu.Unlock()
+	sub.close(nil)
+}
@@ -0,0 +1,83 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/yeeco/gyee/persistent"
+)
+
+func TestEventFeed(t *testing.T) {
+	feed := NewEventFeed()
+	heads := feed.Subscribe(2, EventNewHead)
+	txs := feed.Subscribe(1, EventNewPendingTx, EventSealedTxs)
+
+	feed.Send(&Event{Type: EventNewHead})
+	feed.Send(&Event{Type: EventSealedTxs, Sealed: &SealedTxs{Height: 1}})
+	if ev := <-heads.Chan(); ev.Type != EventNewHead {
+		t.Errorf("heads got %v", ev.Type)
+	}
+	if ev := <-txs.Chan(); ev.Type != EventSealedTxs || ev.Sealed.Height != 1 {
+		t.Errorf("txs got %v", ev.Type)
+	}
+
+	// slow subscriber dropped, others kept
+	feed.Send(&Event{Type: EventNewPendingTx})
+	feed.Send(&Event{Type: EventNewPendingTx})
+	if _, ok := <-txs.Chan(); !ok {
+		t.Fatalf("buffered event lost")
+	}
+	if _, ok := <-txs.Chan(); ok || txs.Err() != ErrSubscriptionSlow {
+		t.Errorf("slow subscriber not dropped, %v", txs.Err())
+	}
+	if feed.Len() != 1 {
+		t.Errorf("feed got %d subscribers", feed.Len())
+	}
+
+	heads.Unsubscribe()
+	heads.Unsubscribe()
+	if _, ok := <-heads.Chan(); ok || heads.Err() != nil {
+		t.Errorf("unsubscribed got %v", heads.Err())
+	}
+	feed.Send(&Event{Type: EventNewHead})
+
+	var nilFeed *EventFeed
+	nilFeed.Send(&Event{Type: EventNewHead})
+}
+
+func TestBlockChainPublishNewHead(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	chain := tv.newChain(t, persistent.NewMemoryStorage())
+	chain.feed = NewEventFeed()
+	sub := chain.feed.Subscribe(4, EventNewHead)
+	defer sub.Unsubscribe()
+
+	b := tv.nextBlock(t, chain, chain.LastBlock(), Transactions{tv.newTx(t, 0, 1, 0, 10)})
+	if err := chain.AddBlock(b); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+	select {
+	case ev := <-sub.Chan():
+		if ev.Block.Hash() != b.Hash() {
+			t.Errorf("new head got %v", ev.Block.Number())
+		}
+	default:
+		t.Errorf("new head not published")
+	}
+}
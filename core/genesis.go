@@ -135,6 +135,12 @@ func (g *Genesis) Commit(stateDB state.Database, putter persistent.Putter) (*Blo
 	if err := b.Write(putter); err != nil {
 		return nil, err
 	}
+	// genesis state always kept in storage
+	for _, root := range []common.Hash{b.StateRoot(), b.ConsensusRoot()} {
+		if err := stateDB.TrieDB().Commit(root, false); err != nil {
+			return nil, err
+		}
+	}
 	putLastBlock(putter, b.Hash())
 	return b, nil
 }
@@ -22,7 +22,11 @@ type Account struct {
 	// account transaction nonce start from 0
 	Nonce uint64 `protobuf:"varint,1,opt,name=nonce,proto3" json:"nonce,omitempty"`
 	// account balance encoded big-endian bytes with math/big/Int.Bytes()
-	Balance              []byte   `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Balance []byte `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	// root hash of account storage trie, empty for no storage
+	StorageRoot []byte `protobuf:"bytes,3,opt,name=storage_root,json=storageRoot,proto3" json:"storage_root,omitempty"`
+	// hash of account contract code, empty for no code
+	CodeHash             []byte   `protobuf:"bytes,4,opt,name=code_hash,json=codeHash,proto3" json:"code_hash,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -32,7 +36,7 @@ func (m *Account) Reset()         { *m = Account{} }
 func (m *Account) String() string { return proto.CompactTextString(m) }
 func (*Account) ProtoMessage()    {}
 func (*Account) Descriptor() ([]byte, []int) {
-	return fileDescriptor_block_dc06e4ac52b7100d, []int{0}
+	return fileDescriptor_block_9c70907c703a33ec, []int{0}
 }
 func (m *Account) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Account.Unmarshal(m, b)
@@ -66,6 +70,20 @@ func (m *Account) GetBalance() []byte {
 	return nil
 }
 
+func (m *Account) GetStorageRoot() []byte {
+	if m != nil {
+		return m.StorageRoot
+	}
+	return nil
+}
+
+func (m *Account) GetCodeHash() []byte {
+	if m != nil {
+		return m.CodeHash
+	}
+	return nil
+}
+
 // signature for a block header or transaction
 type Signature struct {
 	// signer address
@@ -85,7 +103,7 @@ func (m *Signature) Reset()         { *m = Signature{} }
 func (m *Signature) String() string { return proto.CompactTextString(m) }
 func (*Signature) ProtoMessage()    {}
 func (*Signature) Descriptor() ([]byte, []int) {
-	return fileDescriptor_block_dc06e4ac52b7100d, []int{1}
+	return fileDescriptor_block_9c70907c703a33ec, []int{1}
 }
 func (m *Signature) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Signature.Unmarshal(m, b)
@@ -135,6 +153,14 @@ type Transaction struct {
 	Recipient []byte `protobuf:"bytes,3,opt,name=recipient,proto3" json:"recipient,omitempty"`
 	// transaction amount
 	Amount []byte `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	// transaction fee, paid to block validators
+	Fee []byte `protobuf:"bytes,5,opt,name=fee,proto3" json:"fee,omitempty"`
+	// transaction type, 0 for plain transfer
+	Type uint32 `protobuf:"varint,6,opt,name=type,proto3" json:"type,omitempty"`
+	// contract code to deploy, or input of contract call
+	Payload []byte `protobuf:"bytes,7,opt,name=payload,proto3" json:"payload,omitempty"`
+	// max gas of contract execution
+	GasLimit uint64 `protobuf:"varint,8,opt,name=gasLimit,proto3" json:"gasLimit,omitempty"`
 	// signature with LAST MESSAGE TAG of one byte
 	Signature            *Signature `protobuf:"bytes,15,opt,name=signature,proto3" json:"signature,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -146,7 +172,7 @@ func (m *Transaction) Reset()         { *m = Transaction{} }
 func (m *Transaction) String() string { return proto.CompactTextString(m) }
 func (*Transaction) ProtoMessage()    {}
 func (*Transaction) Descriptor() ([]byte, []int) {
-	return fileDescriptor_block_dc06e4ac52b7100d, []int{2}
+	return fileDescriptor_block_9c70907c703a33ec, []int{2}
 }
 func (m *Transaction) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Transaction.Unmarshal(m, b)
@@ -194,6 +220,34 @@ func (m *Transaction) GetAmount() []byte {
 	return nil
 }
 
+func (m *Transaction) GetFee() []byte {
+	if m != nil {
+		return m.Fee
+	}
+	return nil
+}
+
+func (m *Transaction) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *Transaction) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *Transaction) GetGasLimit() uint64 {
+	if m != nil {
+		return m.GasLimit
+	}
+	return 0
+}
+
 func (m *Transaction) GetSignature() *Signature {
 	if m != nil {
 		return m.Signature
@@ -221,7 +275,7 @@ func (m *SignedBlockHeader) Reset()         { *m = SignedBlockHeader{} }
 func (m *SignedBlockHeader) String() string { return proto.CompactTextString(m) }
 func (*SignedBlockHeader) ProtoMessage()    {}
 func (*SignedBlockHeader) Descriptor() ([]byte, []int) {
-	return fileDescriptor_block_dc06e4ac52b7100d, []int{3}
+	return fileDescriptor_block_9c70907c703a33ec, []int{3}
 }
 func (m *SignedBlockHeader) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_SignedBlockHeader.Unmarshal(m, b)
@@ -276,7 +330,7 @@ func (m *BlockBody) Reset()         { *m = BlockBody{} }
 func (m *BlockBody) String() string { return proto.CompactTextString(m) }
 func (*BlockBody) ProtoMessage()    {}
 func (*BlockBody) Descriptor() ([]byte, []int) {
-	return fileDescriptor_block_dc06e4ac52b7100d, []int{4}
+	return fileDescriptor_block_9c70907c703a33ec, []int{4}
 }
 func (m *BlockBody) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_BlockBody.Unmarshal(m, b)
@@ -315,7 +369,7 @@ func (m *Block) Reset()         { *m = Block{} }
 func (m *Block) String() string { return proto.CompactTextString(m) }
 func (*Block) ProtoMessage()    {}
 func (*Block) Descriptor() ([]byte, []int) {
-	return fileDescriptor_block_dc06e4ac52b7100d, []int{5}
+	return fileDescriptor_block_9c70907c703a33ec, []int{5}
 }
 func (m *Block) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_Block.Unmarshal(m, b)
@@ -349,6 +403,149 @@ func (m *Block) GetBody() *BlockBody {
 	return nil
 }
 
+// execution result for a transaction sealed in block
+type Receipt struct {
+	// transaction hash
+	TxHash []byte `protobuf:"bytes,1,opt,name=txHash,proto3" json:"txHash,omitempty"`
+	// execution status, 1 for success, 0 for failure
+	Status uint32 `protobuf:"varint,2,opt,name=status,proto3" json:"status,omitempty"`
+	// failure reason code if status is failure
+	FailReason uint32 `protobuf:"varint,3,opt,name=failReason,proto3" json:"failReason,omitempty"`
+	// block number the transaction was sealed in
+	BlockNumber uint64 `protobuf:"varint,4,opt,name=blockNumber,proto3" json:"blockNumber,omitempty"`
+	// index of the transaction in block
+	Index uint32 `protobuf:"varint,5,opt,name=index,proto3" json:"index,omitempty"`
+	// fee charged from sender, empty if failed before execution
+	Fee []byte `protobuf:"bytes,6,opt,name=fee,proto3" json:"fee,omitempty"`
+	// gas used by contract execution
+	GasUsed uint64 `protobuf:"varint,7,opt,name=gasUsed,proto3" json:"gasUsed,omitempty"`
+	// address of contract deployed
+	ContractAddress      []byte   `protobuf:"bytes,8,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Receipt) Reset()         { *m = Receipt{} }
+func (m *Receipt) String() string { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()    {}
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_block_9c70907c703a33ec, []int{6}
+}
+func (m *Receipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Receipt.Unmarshal(m, b)
+}
+func (m *Receipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Receipt.Marshal(b, m, deterministic)
+}
+func (dst *Receipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Receipt.Merge(dst, src)
+}
+func (m *Receipt) XXX_Size() int {
+	return xxx_messageInfo_Receipt.Size(m)
+}
+func (m *Receipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_Receipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Receipt proto.InternalMessageInfo
+
+func (m *Receipt) GetTxHash() []byte {
+	if m != nil {
+		return m.TxHash
+	}
+	return nil
+}
+
+func (m *Receipt) GetStatus() uint32 {
+	if m != nil {
+		return m.Status
+	}
+	return 0
+}
+
+func (m *Receipt) GetFailReason() uint32 {
+	if m != nil {
+		return m.FailReason
+	}
+	return 0
+}
+
+func (m *Receipt) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *Receipt) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *Receipt) GetFee() []byte {
+	if m != nil {
+		return m.Fee
+	}
+	return nil
+}
+
+func (m *Receipt) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *Receipt) GetContractAddress() []byte {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return nil
+}
+
+// message for
+//   consecutive block headers, exchanged while syncing chain
+type SignedBlockHeaders struct {
+	Headers              []*SignedBlockHeader `protobuf:"bytes,1,rep,name=headers,proto3" json:"headers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *SignedBlockHeaders) Reset()         { *m = SignedBlockHeaders{} }
+func (m *SignedBlockHeaders) String() string { return proto.CompactTextString(m) }
+func (*SignedBlockHeaders) ProtoMessage()    {}
+func (*SignedBlockHeaders) Descriptor() ([]byte, []int) {
+	return fileDescriptor_block_9c70907c703a33ec, []int{7}
+}
+func (m *SignedBlockHeaders) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SignedBlockHeaders.Unmarshal(m, b)
+}
+func (m *SignedBlockHeaders) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SignedBlockHeaders.Marshal(b, m, deterministic)
+}
+func (dst *SignedBlockHeaders) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SignedBlockHeaders.Merge(dst, src)
+}
+func (m *SignedBlockHeaders) XXX_Size() int {
+	return xxx_messageInfo_SignedBlockHeaders.Size(m)
+}
+func (m *SignedBlockHeaders) XXX_DiscardUnknown() {
+	xxx_messageInfo_SignedBlockHeaders.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SignedBlockHeaders proto.InternalMessageInfo
+
+func (m *SignedBlockHeaders) GetHeaders() []*SignedBlockHeader {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Account)(nil), "corepb.Account")
 	proto.RegisterType((*Signature)(nil), "corepb.Signature")
@@ -356,32 +553,47 @@ func init() {
 	proto.RegisterType((*SignedBlockHeader)(nil), "corepb.SignedBlockHeader")
 	proto.RegisterType((*BlockBody)(nil), "corepb.BlockBody")
 	proto.RegisterType((*Block)(nil), "corepb.Block")
-}
-
-func init() { proto.RegisterFile("block.proto", fileDescriptor_block_dc06e4ac52b7100d) }
-
-var fileDescriptor_block_dc06e4ac52b7100d = []byte{
-	// 346 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x92, 0x4f, 0x6b, 0xe3, 0x30,
-	0x10, 0xc5, 0x71, 0x9c, 0x3f, 0x78, 0xec, 0x90, 0x8d, 0x58, 0x16, 0x2f, 0xe4, 0x60, 0x0c, 0x05,
-	0xf7, 0x92, 0x92, 0x14, 0x0a, 0x3d, 0x26, 0xf4, 0xd0, 0x5e, 0xd5, 0xde, 0x8b, 0x2c, 0x0b, 0x47,
-	0xd4, 0x91, 0x82, 0xa4, 0x10, 0xf2, 0x7d, 0xfa, 0x41, 0x8b, 0xe4, 0xd8, 0x51, 0xa0, 0x37, 0xbf,
-	0xb1, 0xe7, 0xcd, 0xef, 0x3d, 0x0c, 0x71, 0xd9, 0x48, 0xfa, 0xb5, 0x3c, 0x28, 0x69, 0x24, 0x1a,
-	0x53, 0xa9, 0xd8, 0xa1, 0xcc, 0x9f, 0x61, 0xb2, 0xa1, 0x54, 0x1e, 0x85, 0x41, 0x7f, 0x61, 0x24,
-	0xa4, 0xa0, 0x2c, 0x0d, 0xb2, 0xa0, 0x18, 0xe2, 0x56, 0xa0, 0x14, 0x26, 0x25, 0x69, 0x88, 0x9d,
-	0x0f, 0xb2, 0xa0, 0x48, 0x70, 0x27, 0x73, 0x06, 0xd1, 0x3b, 0xaf, 0x05, 0x31, 0x47, 0xc5, 0xd0,
-	0x3f, 0x18, 0x6b, 0x5e, 0x0b, 0xa6, 0xdc, 0x76, 0x82, 0x2f, 0x0a, 0xe5, 0x90, 0x68, 0x5e, 0x6f,
-	0x9a, 0x5a, 0x2a, 0x6e, 0x76, 0x7b, 0xe7, 0x31, 0xc5, 0x37, 0x33, 0xb4, 0x80, 0x48, 0x77, 0x46,
-	0x69, 0xe8, 0xd6, 0xaf, 0x83, 0xfc, 0x3b, 0x80, 0xf8, 0x43, 0x11, 0xa1, 0x09, 0x35, 0x5c, 0x0a,
-	0x0b, 0x44, 0x77, 0x84, 0x8b, 0xb7, 0x17, 0x77, 0x6a, 0x8a, 0x3b, 0x79, 0x0d, 0x30, 0xf0, 0x03,
-	0x2c, 0x20, 0x52, 0x8c, 0xf2, 0x03, 0x67, 0xc2, 0x74, 0xee, 0xfd, 0xc0, 0x72, 0x93, 0xbd, 0x8d,
-	0x9f, 0x0e, 0x5b, 0xee, 0x56, 0xa1, 0x07, 0x9f, 0x69, 0x96, 0x05, 0x45, 0xbc, 0x9e, 0x2f, 0xdb,
-	0xce, 0x96, 0x7d, 0x6a, 0x1f, 0xd3, 0xc0, 0xdc, 0xce, 0x59, 0xb5, 0xb5, 0x2d, 0xbf, 0x32, 0x52,
-	0x31, 0x65, 0xdd, 0x77, 0xee, 0xa9, 0x6b, 0xa5, 0x55, 0x96, 0xb4, 0x6c, 0xa4, 0xdc, 0x5f, 0x2a,
-	0x6d, 0x05, 0x5a, 0x01, 0xf4, 0x7e, 0x3a, 0x0d, 0xb3, 0xf0, 0xf7, 0xa3, 0xde, 0x47, 0xf9, 0x13,
-	0x44, 0xee, 0xde, 0x56, 0x56, 0x67, 0x74, 0x0f, 0x7f, 0x14, 0x39, 0x7d, 0x9a, 0x6b, 0x59, 0x3a,
-	0x0d, 0xb2, 0xb0, 0x48, 0xf0, 0x4c, 0x91, 0x93, 0xd7, 0xa1, 0xce, 0x09, 0x8c, 0xdc, 0x1e, 0x5a,
-	0xdd, 0x10, 0xc6, 0xeb, 0xff, 0xfe, 0xbd, 0x9b, 0x30, 0x3d, 0xfc, 0x1d, 0x0c, 0x4b, 0x59, 0x9d,
-	0x1d, 0xbb, 0x07, 0xd8, 0x73, 0x60, 0xf7, 0xba, 0x1c, 0xbb, 0x1f, 0xed, 0xf1, 0x27, 0x00, 0x00,
-	0xff, 0xff, 0xb0, 0xfd, 0x05, 0xd1, 0x77, 0x02, 0x00, 0x00,
+	proto.RegisterType((*Receipt)(nil), "corepb.Receipt")
+	proto.RegisterType((*SignedBlockHeaders)(nil), "corepb.SignedBlockHeaders")
+}
+
+func init() { proto.RegisterFile("block.proto", fileDescriptor_block_9c70907c703a33ec) }
+
+var fileDescriptor_block_9c70907c703a33ec = []byte{
+	// 551 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0x51, 0x6b, 0xdb, 0x3c,
+	0x14, 0xc5, 0x8d, 0x1b, 0x27, 0x37, 0x0e, 0x69, 0xc5, 0xc7, 0x87, 0xb7, 0x95, 0xe1, 0x19, 0x06,
+	0xd9, 0x4b, 0x46, 0x5b, 0xd8, 0x7b, 0xca, 0x1e, 0x5a, 0x18, 0x7b, 0xd0, 0xb6, 0xe7, 0x20, 0xcb,
+	0xb7, 0xb6, 0x98, 0x23, 0x19, 0x49, 0xa1, 0xcd, 0xdb, 0xfe, 0xed, 0xfe, 0xc6, 0x90, 0x6c, 0x27,
+	0xce, 0x36, 0xf6, 0xa6, 0x73, 0xae, 0xa2, 0x7b, 0xee, 0xb9, 0x27, 0x86, 0x59, 0x5e, 0x2b, 0xfe,
+	0x7d, 0xd5, 0x68, 0x65, 0x15, 0x19, 0x73, 0xa5, 0xb1, 0xc9, 0xb3, 0x3d, 0x44, 0x6b, 0xce, 0xd5,
+	0x4e, 0x5a, 0xf2, 0x1f, 0x9c, 0x4b, 0x25, 0x39, 0x26, 0x41, 0x1a, 0x2c, 0x43, 0xda, 0x02, 0x92,
+	0x40, 0x94, 0xb3, 0x9a, 0x39, 0xfe, 0x2c, 0x0d, 0x96, 0x31, 0xed, 0x21, 0x79, 0x03, 0xb1, 0xb1,
+	0x4a, 0xb3, 0x12, 0x37, 0x5a, 0x29, 0x9b, 0x8c, 0x7c, 0x79, 0xd6, 0x71, 0x54, 0x29, 0x4b, 0x5e,
+	0xc1, 0x94, 0xab, 0x02, 0x37, 0x15, 0x33, 0x55, 0x12, 0xfa, 0xfa, 0xc4, 0x11, 0xf7, 0xcc, 0x54,
+	0x19, 0xc2, 0xf4, 0x8b, 0x28, 0x25, 0xb3, 0x3b, 0x8d, 0xe4, 0x7f, 0x18, 0x1b, 0x51, 0x4a, 0xd4,
+	0xbe, 0x7b, 0x4c, 0x3b, 0x44, 0x32, 0x88, 0x8d, 0x28, 0xd7, 0x75, 0xa9, 0xb4, 0xb0, 0xd5, 0xd6,
+	0x6b, 0x98, 0xd3, 0x13, 0x8e, 0x5c, 0xc1, 0xd4, 0xf4, 0x0f, 0x75, 0x2a, 0x8e, 0x44, 0xf6, 0xe3,
+	0x0c, 0x66, 0x5f, 0x35, 0x93, 0x86, 0x71, 0x2b, 0x94, 0x74, 0x03, 0xf1, 0x8a, 0x09, 0xf9, 0xf0,
+	0xd1, 0xb7, 0x9a, 0xd3, 0x1e, 0x1e, 0x0d, 0x38, 0x1b, 0x1a, 0x70, 0x05, 0x53, 0x8d, 0x5c, 0x34,
+	0x02, 0x65, 0x3f, 0xe3, 0x91, 0x70, 0xba, 0xd9, 0xd6, 0xd9, 0xd7, 0x8d, 0xd7, 0x21, 0x72, 0x01,
+	0xa3, 0x47, 0xc4, 0xe4, 0xdc, 0x93, 0xee, 0x48, 0x08, 0x84, 0x76, 0xdf, 0x60, 0x32, 0xf6, 0x4d,
+	0xfd, 0xd9, 0x69, 0x69, 0xd8, 0xbe, 0x56, 0xac, 0x48, 0xa2, 0xd6, 0xdc, 0x0e, 0x92, 0x97, 0x30,
+	0x29, 0x99, 0xf9, 0x24, 0xb6, 0xc2, 0x26, 0x13, 0x2f, 0xe7, 0x80, 0xc9, 0xfb, 0xe1, 0xbc, 0x8b,
+	0x34, 0x58, 0xce, 0x6e, 0x2e, 0x57, 0xed, 0x3e, 0x57, 0x07, 0x47, 0x87, 0x16, 0x58, 0xb8, 0x74,
+	0x3c, 0x16, 0x77, 0x2e, 0x01, 0xf7, 0xc8, 0x0a, 0xd4, 0x4e, 0x79, 0xe5, 0x4f, 0xbd, 0xe3, 0x2d,
+	0x72, 0x2e, 0xe4, 0xb5, 0x52, 0xdb, 0x6e, 0xdd, 0x2d, 0x20, 0xd7, 0x00, 0x87, 0xf7, 0x4c, 0x32,
+	0x4a, 0x47, 0x7f, 0x6f, 0x3a, 0xb8, 0x94, 0x7d, 0x80, 0xa9, 0xef, 0x77, 0xa7, 0x8a, 0x3d, 0x79,
+	0x07, 0x17, 0x9a, 0x3d, 0x6d, 0xec, 0x71, 0x11, 0x26, 0x09, 0xd2, 0xd1, 0x32, 0xa6, 0x0b, 0xcd,
+	0x9e, 0x06, 0xfb, 0x31, 0x19, 0x83, 0x73, 0xff, 0x3b, 0x72, 0x7d, 0xa2, 0x70, 0x76, 0xf3, 0x62,
+	0xd8, 0xef, 0x64, 0x98, 0x83, 0xf8, 0xb7, 0x10, 0xe6, 0xaa, 0xd8, 0x7b, 0xed, 0x03, 0x81, 0x07,
+	0x1d, 0xd4, 0x97, 0xb3, 0x9f, 0x01, 0x44, 0x14, 0x39, 0x8a, 0xc6, 0x6f, 0xd0, 0x3e, 0xbb, 0x40,
+	0xf6, 0x3e, 0xb4, 0xc8, 0xf1, 0xc6, 0x32, 0xbb, 0x33, 0x5d, 0xe6, 0x3a, 0x44, 0x5e, 0x03, 0x3c,
+	0x32, 0x51, 0x53, 0x64, 0x46, 0x49, 0x1f, 0x88, 0x39, 0x1d, 0x30, 0x24, 0xed, 0xfe, 0x68, 0x9f,
+	0x77, 0xdb, 0x1c, 0xb5, 0x8f, 0x45, 0x48, 0x87, 0x94, 0x73, 0x58, 0xc8, 0x02, 0x9f, 0x7d, 0x3a,
+	0xe6, 0xb4, 0x05, 0x7d, 0x62, 0xc6, 0xc7, 0xc4, 0x24, 0x10, 0x95, 0xcc, 0x7c, 0x33, 0xd8, 0xa6,
+	0x23, 0xa4, 0x3d, 0x24, 0x4b, 0x58, 0x70, 0x25, 0xad, 0x66, 0xdc, 0xae, 0x8b, 0x42, 0xa3, 0x31,
+	0x3e, 0x24, 0x31, 0xfd, 0x9d, 0xce, 0x1e, 0x80, 0xfc, 0xe1, 0x96, 0x21, 0xb7, 0x10, 0xb5, 0x86,
+	0xb5, 0x4b, 0xf8, 0xa7, 0xb5, 0xfd, 0xcd, 0x7c, 0xec, 0xbf, 0x1c, 0xb7, 0xbf, 0x06, 0x00, 0xc1,
+	0xef, 0x82, 0x01, 0x48, 0x04, 0x00, 0x00,
 }
@@ -0,0 +1,218 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/core/pb"
+	"github.com/yeeco/gyee/persistent"
+)
+
+var (
+	ErrInvalidProtoToReceipt = errors.New("failed to parse ProtoBuf msg to Receipt")
+)
+
+// ReceiptStatus indicates whether a sealed tx was applied to state
+type ReceiptStatus uint32
+
+const (
+	ReceiptStatusFailed ReceiptStatus = iota
+	ReceiptStatusSuccess
+)
+
+func (s ReceiptStatus) String() string {
+	switch s {
+	case ReceiptStatusFailed:
+		return "failed"
+	case ReceiptStatusSuccess:
+		return "success"
+	default:
+		return fmt.Sprintf("status(%d)", uint32(s))
+	}
+}
+
+// ReceiptFailReason tells why a sealed tx failed to apply
+//   txs without sender, with nonce mismatch or not affording the fee
+//   are dropped before sealing, left in pool and get no receipt
+type ReceiptFailReason uint32
+
+const (
+	ReceiptFailNone ReceiptFailReason = iota
+	ReceiptFailInsufficientBalance
+	ReceiptFailNoRecipient
+	ReceiptFailContract
+)
+
+func (r ReceiptFailReason) String() string {
+	switch r {
+	case ReceiptFailNone:
+		return "none"
+	case ReceiptFailInsufficientBalance:
+		return "insufficient balance"
+	case ReceiptFailNoRecipient:
+		return "recipient missing"
+	case ReceiptFailContract:
+		return "contract execution failed"
+	default:
+		return fmt.Sprintf("reason(%d)", uint32(r))
+	}
+}
+
+// Receipt records execution result of a tx sealed in block
+type Receipt struct {
+	txHash      common.Hash
+	status      ReceiptStatus
+	failReason  ReceiptFailReason
+	blockNumber uint64
+	index       uint32
+	fee         *big.Int
+
+	// contract txs
+	gasUsed         uint64
+	contractAddress *common.Address
+
+	// caches
+	raw []byte
+}
+
+func newReceipt(tx *Transaction, number uint64, index int) *Receipt {
+	return &Receipt{
+		txHash:      *tx.Hash(),
+		status:      ReceiptStatusSuccess,
+		blockNumber: number,
+		index:       uint32(index),
+	}
+}
+
+func (r *Receipt) String() string {
+	return fmt.Sprintf("receipt{tx:[%v] %v@%d:%d %v}",
+		r.txHash, r.status, r.blockNumber, r.index, r.failReason)
+}
+
+func (r *Receipt) TxHash() common.Hash           { return r.txHash }
+func (r *Receipt) Status() ReceiptStatus         { return r.status }
+func (r *Receipt) FailReason() ReceiptFailReason { return r.failReason }
+func (r *Receipt) BlockNumber() uint64           { return r.blockNumber }
+func (r *Receipt) Index() uint32                 { return r.index }
+func (r *Receipt) Succeeded() bool               { return r.status == ReceiptStatusSuccess }
+
+// gas used by contract execution
+func (r *Receipt) GasUsed() uint64 { return r.gasUsed }
+
+// address of contract deployed, nil if none
+func (r *Receipt) ContractAddress() *common.Address { return r.contractAddress }
+
+// fee charged from sender
+func (r *Receipt) Fee() *big.Int {
+	if r.fee == nil {
+		return new(big.Int)
+	}
+	return r.fee
+}
+
+func (r *Receipt) setFailed(reason ReceiptFailReason) {
+	r.status = ReceiptStatusFailed
+	r.failReason = reason
+}
+
+func (r *Receipt) ToProto() *corepb.Receipt {
+	pbr := &corepb.Receipt{
+		TxHash:      common.CopyBytes(r.txHash[:]),
+		Status:      uint32(r.status),
+		FailReason:  uint32(r.failReason),
+		BlockNumber: r.blockNumber,
+		Index:       r.index,
+		Fee:         r.Fee().Bytes(),
+		GasUsed:     r.gasUsed,
+	}
+	if r.contractAddress != nil {
+		pbr.ContractAddress = common.CopyBytes(r.contractAddress[:])
+	}
+	return pbr
+}
+
+func (r *Receipt) FromProto(msg proto.Message) error {
+	pbr, ok := msg.(*corepb.Receipt)
+	if !ok || pbr == nil {
+		return ErrInvalidProtoToReceipt
+	}
+	r.txHash = common.BytesToHash(pbr.TxHash)
+	r.status = ReceiptStatus(pbr.Status)
+	r.failReason = ReceiptFailReason(pbr.FailReason)
+	r.blockNumber = pbr.BlockNumber
+	r.index = pbr.Index
+	r.fee = new(big.Int).SetBytes(pbr.Fee)
+	r.gasUsed = pbr.GasUsed
+	if len(pbr.ContractAddress) > 0 {
+		r.contractAddress = new(common.Address)
+		r.contractAddress.SetBytes(pbr.ContractAddress)
+	}
+	return nil
+}
+
+func (r *Receipt) Encode() ([]byte, error) {
+	return proto.Marshal(r.ToProto())
+}
+
+func (r *Receipt) Decode(enc []byte) error {
+	pb := new(corepb.Receipt)
+	if err := proto.Unmarshal(enc, pb); err != nil {
+		return err
+	}
+	return r.FromProto(pb)
+}
+
+type Receipts []*Receipt
+
+func (rs Receipts) Len() int { return len(rs) }
+
+func (rs Receipts) GetEncoded(index int) []byte { return rs[index].raw }
+
+func (rs Receipts) encode() error {
+	for i := range rs {
+		if rs[i].raw != nil {
+			continue
+		}
+		enc, err := rs[i].Encode()
+		if err != nil {
+			return err
+		}
+		rs[i].raw = enc
+	}
+	return nil
+}
+
+// root hash of receipts, to be committed in header ReceiptsRoot
+func (rs Receipts) hash() (common.Hash, error) {
+	if err := rs.encode(); err != nil {
+		return common.EmptyHash, err
+	}
+	return DeriveHash(rs), nil
+}
+
+func (rs Receipts) Write(putter persistent.Putter) error {
+	for _, r := range rs {
+		putReceipt(putter, r.txHash, r.ToProto())
+	}
+	return nil
+}
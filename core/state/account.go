@@ -21,13 +21,16 @@
 package state
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/common/trie"
 	"github.com/yeeco/gyee/core/pb"
+	sha3 "github.com/yeeco/gyee/crypto/hash"
 	"github.com/yeeco/gyee/log"
 )
 
@@ -41,15 +44,26 @@ type accountObj struct {
 	nonce   uint64
 	balance *big.Int
 
-	//TODO: contract部分的数据
+	// contract code, loaded on first access
+	codeHash  common.Hash
+	code      []byte
+	codeDirty bool
+
+	// storage trie, opened on first access
+	storageRoot   common.Hash
+	storageTrie   Trie
+	cachedStorage map[common.Hash]common.Hash // loaded from storage trie
+	dirtyStorage  map[common.Hash]common.Hash // pending write to storage trie
 }
 
 func newAccount(trie *accountTrie, address common.Address) *accountObj {
 	return &accountObj{
-		trie:    trie,
-		dirty:   true,
-		address: address,
-		balance: new(big.Int),
+		trie:          trie,
+		dirty:         true,
+		address:       address,
+		balance:       new(big.Int),
+		cachedStorage: make(map[common.Hash]common.Hash),
+		dirtyStorage:  make(map[common.Hash]common.Hash),
 	}
 }
 
@@ -103,11 +117,137 @@ func (acc *accountObj) SubBalance(value *big.Int) {
 	acc.SetBalance(new(big.Int).Sub(acc.balance, value))
 }
 
+func (acc *accountObj) Code() []byte {
+	if acc.code != nil || acc.codeHash == common.EmptyHash {
+		return acc.code
+	}
+	code, err := acc.trie.db.TrieDB().Node(acc.codeHash)
+	if err != nil {
+		log.Error("failed to load code", "addr", acc.address, "codeHash", acc.codeHash, "err", err)
+		acc.trie.setTrieErr(err)
+		return nil
+	}
+	acc.code = code
+	return code
+}
+
+func (acc *accountObj) CodeHash() common.Hash {
+	return acc.codeHash
+}
+
+func (acc *accountObj) SetCode(code []byte) {
+	if len(code) == 0 {
+		acc.code = nil
+		acc.codeHash = common.EmptyHash
+	} else {
+		acc.code = common.CopyBytes(code)
+		acc.codeHash = common.BytesToHash(sha3.Sha3256(code))
+	}
+	acc.codeDirty = acc.code != nil
+	acc.dirty = true
+}
+
+func (acc *accountObj) StorageRoot() common.Hash {
+	return acc.storageRoot
+}
+
+func (acc *accountObj) GetStorage(key common.Hash) common.Hash {
+	if value, ok := acc.dirtyStorage[key]; ok {
+		return value
+	}
+	if value, ok := acc.cachedStorage[key]; ok {
+		return value
+	}
+	var value common.Hash
+	if tr := acc.getStorageTrie(); tr != nil {
+		enc, err := tr.TryGet(key[:])
+		if err != nil {
+			acc.trie.setTrieErr(err)
+			return value
+		}
+		value.SetBytes(enc)
+	}
+	acc.cachedStorage[key] = value
+	return value
+}
+
+func (acc *accountObj) SetStorage(key, value common.Hash) {
+	if acc.GetStorage(key) == value {
+		return
+	}
+	acc.dirtyStorage[key] = value
+	acc.dirty = true
+}
+
+// open storage trie with storage root
+func (acc *accountObj) getStorageTrie() Trie {
+	if acc.storageTrie == nil {
+		tr, err := acc.trie.db.OpenTrie(acc.storageRoot)
+		if err != nil {
+			log.Error("failed to open storage trie", "addr", acc.address, "root", acc.storageRoot, "err", err)
+			acc.trie.setTrieErr(err)
+			return nil
+		}
+		acc.storageTrie = tr
+	}
+	return acc.storageTrie
+}
+
+// write pending storage to storage trie and commit it, updating storage root
+func (acc *accountObj) commitStorage() error {
+	if len(acc.dirtyStorage) == 0 {
+		return nil
+	}
+	tr := acc.getStorageTrie()
+	if tr == nil {
+		return acc.trie.trieErr
+	}
+	for key, value := range acc.dirtyStorage {
+		var err error
+		if value == common.EmptyHash {
+			err = tr.TryDelete(key[:])
+		} else {
+			// zero bytes trimmed, as trie holds no empty value
+			err = tr.TryUpdate(key[:], bytes.TrimLeft(value[:], "\x00"))
+		}
+		if err != nil {
+			return err
+		}
+		acc.cachedStorage[key] = value
+	}
+	acc.dirtyStorage = make(map[common.Hash]common.Hash)
+
+	root, err := tr.Commit(nil)
+	if err != nil {
+		return err
+	}
+	if root == trie.EmptyRoot {
+		root = common.EmptyHash
+	}
+	acc.storageRoot = root
+	return nil
+}
+
+// write contract code into trie database, referenced by account trie leaf
+func (acc *accountObj) commitCode() {
+	if !acc.codeDirty {
+		return
+	}
+	acc.trie.db.TrieDB().InsertBlob(acc.codeHash, acc.code)
+	acc.codeDirty = false
+}
+
 func (acc *accountObj) ToBytes() ([]byte, error) {
 	pbAcc := &corepb.Account{
 		Nonce:   acc.nonce,
 		Balance: acc.balance.Bytes(),
 	}
+	if acc.storageRoot != common.EmptyHash {
+		pbAcc.StorageRoot = acc.storageRoot[:]
+	}
+	if acc.codeHash != common.EmptyHash {
+		pbAcc.CodeHash = acc.codeHash[:]
+	}
 	bytes, err := proto.Marshal(pbAcc)
 	if err != nil {
 		return nil, err
@@ -127,6 +267,8 @@ func (acc *accountObj) setBytes(bytes []byte) error {
 	}
 	acc.nonce = pbAcc.Nonce
 	acc.balance.Set(value)
+	acc.storageRoot = common.BytesToHash(pbAcc.StorageRoot)
+	acc.codeHash = common.BytesToHash(pbAcc.CodeHash)
 	return nil
 }
 
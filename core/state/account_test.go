@@ -0,0 +1,85 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/persistent"
+)
+
+func TestAccountStorage(t *testing.T) {
+	storage := persistent.NewMemoryStorage()
+	db := NewDatabase(storage)
+	at, err := NewAccountTrie(common.EmptyHash, db)
+	if err != nil {
+		t.Fatalf("NewAccountTrie() %v", err)
+	}
+	var (
+		addr  = common.BytesToAddress([]byte{1})
+		code  = []byte{0x60, 0x00, 0x60, 0x01}
+		key1  = common.BytesToHash([]byte{1})
+		key2  = common.BytesToHash([]byte{2})
+		value = common.BytesToHash([]byte{0xaa, 0xbb})
+	)
+	account := at.GetAccount(addr, true)
+	account.SetCode(code)
+	account.SetStorage(key1, value)
+	account.SetStorage(key2, value)
+	root, err := at.Commit()
+	if err != nil {
+		t.Fatalf("Commit() %v", err)
+	}
+	if account.StorageRoot() == common.EmptyHash {
+		t.Fatalf("storage root not updated on commit")
+	}
+
+	// flushed with account trie, read back from storage
+	if err := db.TrieDB().Commit(root, false); err != nil {
+		t.Fatalf("TrieDB().Commit() %v", err)
+	}
+	at, err = NewAccountTrie(root, NewDatabase(storage))
+	if err != nil {
+		t.Fatalf("NewAccountTrie() %v", err)
+	}
+	account = at.GetAccount(addr, false)
+	if account == nil {
+		t.Fatalf("account missing")
+	}
+	if !bytes.Equal(account.Code(), code) {
+		t.Errorf("code got %x", account.Code())
+	}
+	if got := account.GetStorage(key1); got != value {
+		t.Errorf("storage got %x", got)
+	}
+	if got := account.GetStorage(common.BytesToHash([]byte{3})); got != common.EmptyHash {
+		t.Errorf("missing storage got %x", got)
+	}
+
+	// zero value deletes key, storage root reset after all deleted
+	account.SetStorage(key1, common.EmptyHash)
+	account.SetStorage(key2, common.EmptyHash)
+	if _, err := at.Commit(); err != nil {
+		t.Fatalf("Commit() %v", err)
+	}
+	if account.StorageRoot() != common.EmptyHash {
+		t.Errorf("empty storage root got %x", account.StorageRoot())
+	}
+}
@@ -20,8 +20,11 @@ package state
 import (
 	"fmt"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/core/pb"
 	"github.com/yeeco/gyee/log"
+	"github.com/yeeco/gyee/persistent"
 )
 
 type accountTrie struct {
@@ -64,20 +67,32 @@ func (at *accountTrie) Commit() (common.Hash, error) {
 		isDirty := account.dirty
 		switch {
 		case isDirty:
+			if err := account.commitStorage(); err != nil {
+				return common.EmptyHash, err
+			}
+			account.commitCode()
 			at.updateAccount(account)
 		}
 		account.dirty = false
 	}
+	trieDB := at.db.TrieDB()
 	root, err := at.trie.Commit(func(leaf []byte, parent common.Hash) error {
-		// TODO: mark account trie node reference to parent Hash
+		// storage trie and code kept along with account trie node
+		pbAcc := &corepb.Account{}
+		if err := proto.Unmarshal(leaf, pbAcc); err != nil {
+			return nil
+		}
+		if len(pbAcc.StorageRoot) > 0 {
+			trieDB.Reference(common.BytesToHash(pbAcc.StorageRoot), parent)
+		}
+		if len(pbAcc.CodeHash) > 0 {
+			trieDB.Reference(common.BytesToHash(pbAcc.CodeHash), parent)
+		}
 		return nil
 	})
 	if err != nil {
 		return common.EmptyHash, err
 	}
-	if err := at.db.TrieDB().Commit(root, true); err != nil {
-		return common.EmptyHash, err
-	}
 	return root, nil
 }
 
@@ -106,6 +121,10 @@ func (at *accountTrie) GetAccount(address common.Address, createIfMissing bool)
 	return account
 }
 
+func (at *accountTrie) Prove(address common.Address, proofDb persistent.Putter) error {
+	return at.trie.Prove(address[:], 0, proofDb)
+}
+
 //
 // trie ops
 //
@@ -70,9 +70,6 @@ func (ct *consensusTrie) Commit() (common.Hash, error) {
 	if err != nil {
 		return common.EmptyHash, err
 	}
-	if err := ct.db.TrieDB().Commit(root, true); err != nil {
-		return common.EmptyHash, err
-	}
 	return root, nil
 }
 
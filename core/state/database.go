@@ -48,6 +48,7 @@ type Trie interface {
 	Commit(onleaf trie.LeafCallback) (common.Hash, error)
 	Hash() common.Hash
 	NodeIterator(startKey []byte) trie.NodeIterator
+	Prove(key []byte, fromLevel uint, proofDb persistent.Putter) error
 }
 
 func NewDatabase(storage persistent.Storage) Database {
@@ -0,0 +1,43 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/common/trie"
+)
+
+// VerifyAccountProof checks merkle proof of account against state root,
+//   without access to the state trie itself.
+// Returns account nonce and balance, balance is nil if proof shows account absent
+func VerifyAccountProof(root common.Hash, address common.Address, proofDb trie.DatabaseReader) (uint64, *big.Int, error) {
+	enc, _, err := trie.VerifyProof(root, address[:], proofDb)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(enc) == 0 {
+		return 0, nil, nil
+	}
+	account := newAccount(nil, address)
+	if err := account.setBytes(enc); err != nil {
+		return 0, nil, err
+	}
+	return account.Nonce(), account.Balance(), nil
+}
@@ -0,0 +1,71 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/persistent"
+)
+
+func TestAccountProof(t *testing.T) {
+	at, err := NewAccountTrie(common.EmptyHash, NewDatabase(persistent.NewMemoryStorage()))
+	if err != nil {
+		t.Fatalf("NewAccountTrie() %v", err)
+	}
+	for i := 1; i <= 16; i++ {
+		account := at.GetAccount(common.BytesToAddress([]byte{byte(i)}), true)
+		account.SetNonce(uint64(i))
+		account.SetBalance(big.NewInt(int64(i * 100)))
+	}
+	root, err := at.Commit()
+	if err != nil {
+		t.Fatalf("Commit() %v", err)
+	}
+
+	// existing account
+	addr := common.BytesToAddress([]byte{7})
+	proof := persistent.NewMemoryStorage()
+	if err := at.Prove(addr, proof); err != nil {
+		t.Fatalf("Prove() %v", err)
+	}
+	nonce, balance, err := VerifyAccountProof(root, addr, proof)
+	if err != nil {
+		t.Fatalf("VerifyAccountProof() %v", err)
+	}
+	if nonce != 7 || balance == nil || balance.Int64() != 700 {
+		t.Errorf("account proof got nonce %d balance %v", nonce, balance)
+	}
+
+	// proof not matching root
+	if _, _, err := VerifyAccountProof(common.BytesToHash([]byte{1}), addr, proof); err == nil {
+		t.Errorf("proof verified against wrong root")
+	}
+
+	// absent account
+	missing := common.BytesToAddress([]byte{0xff})
+	proof = persistent.NewMemoryStorage()
+	if err := at.Prove(missing, proof); err != nil {
+		t.Fatalf("Prove() %v", err)
+	}
+	if _, balance, err := VerifyAccountProof(root, missing, proof); err != nil || balance != nil {
+		t.Errorf("absence proof got balance %v err %v", balance, err)
+	}
+}
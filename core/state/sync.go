@@ -0,0 +1,47 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/common/trie"
+	"github.com/yeeco/gyee/core/pb"
+)
+
+// NewStateSync creates a scheduler downloading account trie of root,
+//   along with storage tries and code of accounts reached,
+//   skipping nodes already in database
+func NewStateSync(root common.Hash, database trie.DatabaseReader) *trie.Sync {
+	var sync *trie.Sync
+	callback := func(leaf []byte, parent common.Hash) error {
+		pbAcc := &corepb.Account{}
+		if err := proto.Unmarshal(leaf, pbAcc); err != nil {
+			return err
+		}
+		if len(pbAcc.StorageRoot) > 0 {
+			sync.AddSubTrie(common.BytesToHash(pbAcc.StorageRoot), 64, parent, nil)
+		}
+		if len(pbAcc.CodeHash) > 0 {
+			sync.AddRawEntry(common.BytesToHash(pbAcc.CodeHash), 64, parent)
+		}
+		return nil
+	}
+	sync = trie.NewSync(root, database, callback)
+	return sync
+}
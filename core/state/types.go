@@ -22,12 +22,13 @@ import (
 	"math/big"
 
 	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/persistent"
 )
 
 // interface for single account, NO CONCURRENCY
 // 1. cache existing account data in memory
 // 2. cache created / updated account, also providing such operations
-// 3. TODO: handle account storage trie
+// 3. handle account contract code and storage trie, committed with account trie
 type Account interface {
 	// account address
 	Address() *common.Address
@@ -43,6 +44,16 @@ type Account interface {
 	AddBalance(*big.Int)
 	SubBalance(*big.Int)
 
+	// account contract code, nil for none
+	Code() []byte
+	CodeHash() common.Hash
+	SetCode([]byte)
+
+	// account storage, zero value for missing key, setting zero value deletes key
+	StorageRoot() common.Hash
+	GetStorage(key common.Hash) common.Hash
+	SetStorage(key, value common.Hash)
+
 	// binary representation for account used as trie value
 	ToBytes() ([]byte, error)
 }
@@ -54,7 +65,8 @@ type managedTrie interface {
 	// Get trie root without committing
 	Root() common.Hash
 
-	// Commit trie to backing storage
+	// Commit trie to backing trie database,
+	//   nodes kept in memory until flushed to storage by Database.TrieDB()
 	Commit() (root common.Hash, err error)
 }
 
@@ -66,6 +78,10 @@ type AccountTrie interface {
 
 	// Get account from trie, create if requested
 	GetAccount(address common.Address, createIfMissing bool) Account
+
+	// Write merkle proof of account against Root() into proofDb,
+	//   proof of absence if account not in trie
+	Prove(address common.Address, proofDb persistent.Putter) error
 }
 
 type ConsensusTrie interface {
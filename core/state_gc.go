@@ -0,0 +1,128 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/common/prque"
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/persistent"
+)
+
+// State trie nodes of new blocks are kept in memory of trie database.
+// Archive node, the default, flushes state of every block to storage.
+// Pruning node, enabled by config, references state of recent blocks, and
+//   dereferences state out of history, dropping nodes no longer referenced,
+//   flushing a full state every StateFlushInterval blocks, and on chain stop.
+// Consensus tries are small and always flushed, validators of all blocks kept.
+
+const (
+	DefaultStateHistory = 128 // recent blocks with state kept by pruning node
+	DefaultTrieCache    = 64  // MB of dirty trie nodes before flushing oldest to storage
+	DefaultStateCache   = 16  // MB of clean trie nodes cached on reading storage
+
+	// blocks between full state flushed to storage by pruning node
+	StateFlushInterval = 1024
+)
+
+type stateGC struct {
+	history   uint64       // recent blocks with state kept in memory
+	queue     *prque.Prque // state root referenced, by -number
+	lastFlush uint64       // last block with state flushed
+}
+
+// enable / disable state pruning, with 0 history / cache for default
+//   expected to be called before chain started
+func (bc *BlockChain) setStatePruning(enabled bool, history uint64, cache int) {
+	if cache <= 0 {
+		cache = DefaultTrieCache
+	}
+	bc.trieCache = common.StorageSize(cache * 1024 * 1024)
+	if !enabled {
+		bc.stateGC = nil
+		return
+	}
+	if history == 0 {
+		history = DefaultStateHistory
+	}
+	bc.stateGC = &stateGC{
+		history:   history,
+		queue:     prque.New(nil),
+		lastFlush: bc.CurrentBlockHeight(),
+	}
+}
+
+// commit trie nodes of a stored block, chainmu locked
+func (bc *BlockChain) commitState(b *Block) error {
+	triedb := bc.stateDB.TrieDB()
+	if err := triedb.Commit(b.ConsensusRoot(), false); err != nil {
+		return err
+	}
+	if gc := bc.stateGC; gc == nil {
+		if err := triedb.Commit(b.StateRoot(), false); err != nil {
+			return err
+		}
+	} else {
+		triedb.Reference(b.StateRoot(), common.Hash{})
+		gc.queue.Push(b.StateRoot(), -int64(b.Number()))
+
+		if number := b.Number(); number > gc.history {
+			chosen := number - gc.history
+			// flush state periodically, limiting blocks to replay on crash
+			if chosen >= gc.lastFlush+StateFlushInterval {
+				if flushed := bc.GetBlockByNumber(chosen); flushed != nil && flushed.stateTrie != nil {
+					if err := triedb.Commit(flushed.StateRoot(), false); err != nil {
+						return err
+					}
+					gc.lastFlush = chosen
+				}
+			}
+			// drop state out of history
+			for !gc.queue.Empty() {
+				root, prio := gc.queue.Pop()
+				if uint64(-prio) > chosen {
+					gc.queue.Push(root, prio)
+					break
+				}
+				triedb.Dereference(root.(common.Hash))
+			}
+		}
+	}
+	// bound memory held by dirty nodes, including those of blocks not accepted
+	if size, _ := triedb.Size(); size > bc.trieCache {
+		if err := triedb.Cap(bc.trieCache - persistent.IdealBatchSize); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flush state of last block to storage
+func (bc *BlockChain) flushState() error {
+	if bc.stateGC == nil {
+		return nil
+	}
+	bc.chainmu.Lock()
+	defer bc.chainmu.Unlock()
+
+	head := bc.LastBlock()
+	if err := bc.stateDB.TrieDB().Commit(head.StateRoot(), true); err != nil {
+		return err
+	}
+	bc.stateGC.lastFlush = head.Number()
+	return nil
+}
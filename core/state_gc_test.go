@@ -0,0 +1,93 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"testing"
+
+	"github.com/yeeco/gyee/persistent"
+)
+
+func TestStatePruning(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	storage := persistent.NewMemoryStorage()
+	chain := tv.newChain(t, storage)
+	chain.setStatePruning(true, 4, 0)
+	tv.growChain(t, chain, 10)
+
+	for n := uint64(0); n <= 10; n++ {
+		b := chain.GetBlockByNumber(n)
+		if b == nil {
+			t.Fatalf("block %d missing", n)
+		}
+		// genesis in storage, recent blocks in memory
+		kept := n == 0 || n > 10-4
+		if _, err := chain.StateAt(b.StateRoot()); (err == nil) != kept {
+			t.Errorf("state of block %d kept %v, got err %v", n, kept, err)
+		}
+		if len(b.ValidatorAddr()) != 3 {
+			t.Errorf("validators of block %d got %v", n, b.ValidatorAddr())
+		}
+	}
+	// head state flushed on stop
+	chain.Stop()
+	chain, err := NewBlockChain(TestNetID, storage, nil)
+	if err != nil {
+		t.Fatalf("NewBlockChain() %v", err)
+	}
+	if head := chain.LastBlock(); head.Number() != 10 {
+		t.Errorf("reopened chain head got %d", head.Number())
+	}
+	if _, err := chain.StateAt(chain.GetBlockByNumber(9).StateRoot()); err == nil {
+		t.Errorf("state of block 9 not flushed expected missing")
+	}
+
+	// pruned state of rewind target rebuilt from genesis
+	if err := chain.SetHead(2); err != nil {
+		t.Fatalf("SetHead() to pruned state %v", err)
+	}
+	head := chain.LastBlock()
+	if head.Number() != 2 || head.stateTrie == nil {
+		t.Fatalf("SetHead() head %d state missing", head.Number())
+	}
+	if _, err := chain.StateAt(head.StateRoot()); err != nil {
+		t.Errorf("rebuilt state of block 2 missing: %v", err)
+	}
+	tv.growChain(t, chain, 1)
+	if chain.CurrentBlockHeight() != 3 {
+		t.Errorf("grow after SetHead() failed")
+	}
+}
+
+func TestStateArchive(t *testing.T) {
+	tv := newTestValidators(t, 3)
+	storage := persistent.NewMemoryStorage()
+	chain := tv.newChain(t, storage)
+	tv.growChain(t, chain, 10)
+
+	// state of all blocks in storage
+	reopened, err := NewBlockChain(TestNetID, storage, nil)
+	if err != nil {
+		t.Fatalf("NewBlockChain() %v", err)
+	}
+	for n := uint64(0); n <= 10; n++ {
+		if _, err := reopened.StateAt(chain.GetBlockByNumber(n).StateRoot()); err != nil {
+			t.Errorf("state of block %d missing: %v", n, err)
+		}
+	}
+}
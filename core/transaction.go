@@ -43,13 +43,31 @@ var (
 	ErrTxFromMismatch    = errors.New("tx sender mismatch")
 )
 
+// TxType tells how a tx is applied to state
+type TxType uint32
+
+const (
+	TxTypeTransfer       TxType = iota // plain value transfer
+	TxTypeContractDeploy               // deploy payload as contract code, no recipient
+	TxTypeContractCall                 // call recipient contract with payload as input
+)
+
+// fee per gas of contract txs, fee of a contract tx must cover its gas limit
+const GasPrice = 1
+
 type Transaction struct {
 	chainID   uint32
 	nonce     uint64
 	to        *common.Address
 	amount    *big.Int
+	fee       *big.Int
 	signature *crypto.Signature
 
+	// contract txs
+	txType   TxType
+	payload  []byte
+	gasLimit uint64
+
 	// caches
 	from *common.Address
 	hash *common.Hash
@@ -59,15 +77,41 @@ type Transaction struct {
 //最小transaction字节数？
 
 func NewTransaction(chainID uint32, nonce uint64, recipient *common.Address, amount *big.Int) *Transaction {
+	return NewTransactionWithFee(chainID, nonce, recipient, amount, nil)
+}
+
+func NewTransactionWithFee(chainID uint32, nonce uint64, recipient *common.Address, amount *big.Int, fee *big.Int) *Transaction {
 	tx := &Transaction{
 		chainID: chainID,
 		nonce:   nonce,
 		to:      recipient,
 		amount:  new(big.Int),
+		fee:     new(big.Int),
 	}
 	if amount != nil {
 		tx.amount.Set(amount)
 	}
+	if fee != nil {
+		tx.fee.Set(fee)
+	}
+	return tx
+}
+
+// contract deploying tx, contract address derived from sender and nonce
+func NewDeployTransaction(chainID uint32, nonce uint64, code []byte, amount *big.Int, fee *big.Int, gasLimit uint64) *Transaction {
+	tx := NewTransactionWithFee(chainID, nonce, nil, amount, fee)
+	tx.txType = TxTypeContractDeploy
+	tx.payload = common.CopyBytes(code)
+	tx.gasLimit = gasLimit
+	return tx
+}
+
+// contract calling tx
+func NewCallTransaction(chainID uint32, nonce uint64, contract *common.Address, input []byte, amount *big.Int, fee *big.Int, gasLimit uint64) *Transaction {
+	tx := NewTransactionWithFee(chainID, nonce, contract, amount, fee)
+	tx.txType = TxTypeContractCall
+	tx.payload = common.CopyBytes(input)
+	tx.gasLimit = gasLimit
 	return tx
 }
 
@@ -81,7 +125,7 @@ func NewTransactionFromProto(msg proto.Message) (*Transaction, error) {
 }
 
 func (t *Transaction) String() string {
-	return fmt.Sprintf("tx{f:[%v] t:[%v] a:%v}", t.from, t.to, t.amount)
+	return fmt.Sprintf("tx{f:[%v] t:[%v] a:%v fee:%v}", t.from, t.to, t.amount, t.fee)
 }
 
 func (t *Transaction) ChainID() uint32 {
@@ -92,6 +136,11 @@ func (t *Transaction) Nonce() uint64 {
 	return t.nonce
 }
 
+// sender recovered by VerifySig, nil if not verified yet
+func (t *Transaction) From() *common.Address {
+	return t.from
+}
+
 func (t *Transaction) Recipient() *common.Address {
 	return t.to
 }
@@ -100,6 +149,36 @@ func (t *Transaction) Amount() *big.Int {
 	return t.amount
 }
 
+func (t *Transaction) Fee() *big.Int {
+	if t.fee == nil {
+		return new(big.Int)
+	}
+	return t.fee
+}
+
+func (t *Transaction) Type() TxType {
+	return t.txType
+}
+
+func (t *Transaction) Payload() []byte {
+	return t.payload
+}
+
+func (t *Transaction) GasLimit() uint64 {
+	return t.gasLimit
+}
+
+// min fee of tx paying for its gas limit
+func (t *Transaction) GasFee() *big.Int {
+	gasFee := new(big.Int).SetUint64(t.gasLimit)
+	return gasFee.Mul(gasFee, big.NewInt(GasPrice))
+}
+
+// total balance needed by sender, amount + fee
+func (t *Transaction) Cost() *big.Int {
+	return new(big.Int).Add(t.amount, t.Fee())
+}
+
 func (t *Transaction) Sign(signer crypto.Signer) error {
 	sig, err := signer.Sign(t.Hash()[:])
 	if err != nil {
@@ -132,6 +211,14 @@ func (t *Transaction) ToProto() (*corepb.Transaction, error) {
 	if t.amount != nil {
 		pbTx.Amount = t.amount.Bytes()
 	}
+	if t.fee != nil {
+		pbTx.Fee = t.fee.Bytes()
+	}
+	pbTx.Type = uint32(t.txType)
+	if len(t.payload) > 0 {
+		pbTx.Payload = common.CopyBytes(t.payload)
+	}
+	pbTx.GasLimit = t.gasLimit
 	if t.signature != nil {
 		pbTx.Signature = &corepb.Signature{
 			SigAlgorithm: uint32(t.signature.Algorithm),
@@ -160,6 +247,13 @@ func (t *Transaction) FromProto(msg proto.Message) error {
 	if pbt.Amount != nil {
 		t.amount.SetBytes(pbt.Amount)
 	}
+	t.fee = new(big.Int)
+	if pbt.Fee != nil {
+		t.fee.SetBytes(pbt.Fee)
+	}
+	t.txType = TxType(pbt.Type)
+	t.payload = common.CopyBytes(pbt.Payload)
+	t.gasLimit = pbt.GasLimit
 	if pbt.Signature != nil {
 		t.signature = &crypto.Signature{
 			Algorithm: crypto.Algorithm(pbt.Signature.SigAlgorithm),
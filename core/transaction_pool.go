@@ -30,29 +30,59 @@ package core
 
 import (
 	"errors"
+	"math/big"
 	"sync"
+	"time"
 
 	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/core/yvm"
 	"github.com/yeeco/gyee/log"
 	"github.com/yeeco/gyee/p2p"
 )
 
-const TooFarTx = 8192
+const (
+	TooFarTx = 8192
+
+	MaxPoolTxs       = 8192            // max txs in pool, pending and queued
+	MaxAccountTxs    = 256             // max txs in pool of an account
+	QueuedTxLifetime = 3 * time.Minute // max time for a future tx waiting in queue
+
+	txExpireInterval = 30 * time.Second
+)
 
 var (
-	ErrTxChainID = errors.New("transaction chainID mismatch")
+	ErrTxChainID             = errors.New("transaction chainID mismatch")
+	ErrTxKnown               = errors.New("known transaction")
+	ErrTxNoAccount           = errors.New("transaction sender account not exist")
+	ErrTxNonceTooLow         = errors.New("transaction nonce too low")
+	ErrTxNonceTooFar         = errors.New("transaction nonce too far")
+	ErrTxFeeTooLow           = errors.New("transaction fee lower than min fee")
+	ErrTxGasFeeTooLow        = errors.New("transaction fee not covering gas limit")
+	ErrTxGasLimitTooHigh     = errors.New("transaction gas limit too high")
+	ErrTxInsufficientBalance = errors.New("insufficient balance for transaction cost")
+	ErrTxReplaceUnderpriced  = errors.New("replacement transaction underpriced")
+	ErrTxAccountFull         = errors.New("too many transactions of account in pool")
+	ErrTxUnderpriced         = errors.New("transaction pool full, fee too low")
+	ErrTxInvalidSig          = errors.New("transaction signature invalid")
 )
 
+// TransactionPool keeps txs not sealed yet
+//   pending: per account txs with consecutive nonce from account nonce,
+//            executable and sent to consensus engine
+//   queue:   per account future txs, waiting for nonce gap to be filled
 type TransactionPool struct {
 	core       *Core
 	subscriber *p2p.Subscriber
 
-	// requesting tx hash pool
-	reqPool map[common.Hash]struct{}
+	pending map[common.Address]*txList
+	queue   map[common.Address]*txList
+	all     map[common.Hash]*Transaction
+	added   map[common.Hash]time.Time
 
-	// pending tx pool
-	pendingPool map[common.Hash]*Transaction
+	// min fee for tx to be accepted
+	minFee *big.Int
 
+	mu     sync.RWMutex // protects pool content
 	lock   sync.RWMutex
 	quitCh chan struct{}
 	wg     sync.WaitGroup
@@ -61,10 +91,16 @@ type TransactionPool struct {
 func NewTransactionPool(core *Core) (*TransactionPool, error) {
 	log.Info("Create New TransactionPool")
 	bp := &TransactionPool{
-		core:        core,
-		reqPool:     make(map[common.Hash]struct{}),
-		pendingPool: make(map[common.Hash]*Transaction),
-		quitCh:      make(chan struct{}),
+		core:    core,
+		pending: make(map[common.Address]*txList),
+		queue:   make(map[common.Address]*txList),
+		all:     make(map[common.Hash]*Transaction),
+		added:   make(map[common.Hash]time.Time),
+		minFee:  new(big.Int),
+		quitCh:  make(chan struct{}),
+	}
+	if core.config != nil && core.config.Chain != nil {
+		bp.minFee.SetUint64(core.config.Chain.MinTxFee)
 	}
 	return bp, nil
 }
@@ -76,6 +112,7 @@ func (tp *TransactionPool) Start() {
 
 	tp.subscriber = p2p.NewSubscriber(tp, make(chan p2p.Message), p2p.MessageTypeTx)
 	tp.core.node.P2pService().Register(tp.subscriber)
+	tp.core.blockChain.txPool = tp
 
 	go tp.loop()
 }
@@ -85,6 +122,7 @@ func (tp *TransactionPool) Stop() {
 	defer tp.lock.Unlock()
 	log.Info("TransactionPool Stop...")
 
+	tp.core.blockChain.txPool = nil
 	tp.core.node.P2pService().UnRegister(tp.subscriber)
 
 	close(tp.quitCh)
@@ -96,6 +134,9 @@ func (tp *TransactionPool) loop() {
 	tp.wg.Add(1)
 	defer tp.wg.Done()
 
+	expire := time.NewTicker(txExpireInterval)
+	defer expire.Stop()
+
 	for {
 		select {
 		case <-tp.quitCh:
@@ -104,6 +145,8 @@ func (tp *TransactionPool) loop() {
 		case msg := <-tp.subscriber.MsgChan:
 			//log.Info("tx pool receive ", msg.MsgType, " ", msg.From)
 			tp.processMsg(msg)
+		case <-expire.C:
+			tp.expireQueued(time.Now())
 		}
 	}
 }
@@ -124,58 +167,404 @@ func (tp *TransactionPool) processMsg(msg p2p.Message) {
 
 func (tp *TransactionPool) processTx(tx *Transaction) {
 	// validate tx integrity
-	if err := tp.core.blockChain.verifyTx(tx); err != nil {
+	if err := tp.verifyTx(tx); err != nil {
 		log.Warn("processTx() verify fails", "err", err, "tx", tx)
 		// TODO: mark bad peer?
 		return
 	}
-	if err := tx.VerifySig(); err != nil {
-		log.Warn("tx sig verify failed", "err", err)
+
+	switch err := tp.addTx(tx); err {
+	case nil:
+	case ErrTxKnown:
+		// TODO: mark bad peer?
+	default:
+		log.Warn("tx rejected by pool", "err", err, "tx", tx)
 		// TODO: mark bad peer?
-		return
 	}
+}
 
-	// search in-mem request, if we are requesting for this tx
-	if _, ok := tp.reqPool[*tx.hash]; ok {
-		delete(tp.reqPool, *tx.hash)
-		tp.pendingPool[*tx.hash] = tx
+// SubmitTx adds a tx from local client to pool, and broadcasts it if accepted
+//   returns the reason if tx rejected, e.g. ErrTxInvalidSig, ErrTxNonceTooLow
+func (tp *TransactionPool) SubmitTx(tx *Transaction) error {
+	if err := tp.verifyTx(tx); err != nil {
+		return err
+	}
+	if err := tp.addTx(tx); err != nil {
+		return err
+	}
+	tp.TxBroadcast(tx)
+	return nil
+}
 
-		// TODO: check if block can be sealed
-		return
+// check tx integrity, recovering its sender
+func (tp *TransactionPool) verifyTx(tx *Transaction) error {
+	if err := tp.core.blockChain.verifyTx(tx); err != nil {
+		return err
 	}
+	if err := tx.VerifySig(); err != nil {
+		log.Debug("tx sig verify failed", "err", err, "tx", tx)
+		return ErrTxInvalidSig
+	}
+	return nil
+}
+
+// add a verified tx to pool, send to engine if it becomes executable
+func (tp *TransactionPool) addTx(tx *Transaction) error {
+	promoted, err := tp.add(tx)
+	if err != nil {
+		return err
+	}
+	tp.core.feed.Send(&Event{Type: EventNewPendingTx, Tx: tx})
+
+	// put tx to DHT
+	// TODO:
+
+	// send executable tx to consensus
+	tp.sendToEngine(promoted)
+	return nil
+}
+
+func (tp *TransactionPool) add(tx *Transaction) (Transactions, error) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
 
+	hash := *tx.Hash()
+	if _, ok := tp.all[hash]; ok {
+		return nil, ErrTxKnown
+	}
 	// search chain, if tx has been sealed
 	// this may not be sufficient, legacy tx may be dropped from storage
 	// in such cases a nonce check would cover
-	if hasTransaction(tp.core.storage, *tx.Hash()) {
-		// TODO: mark bad peer?
-		return
+	if tp.core.blockChain.txSealed(hash) {
+		return nil, ErrTxKnown
+	}
+	if tx.Fee().Cmp(tp.minFee) < 0 {
+		return nil, ErrTxFeeTooLow
+	}
+	if tx.gasLimit > yvm.MaxGasLimit {
+		return nil, ErrTxGasLimitTooHigh
+	}
+	if tx.Fee().Cmp(tx.GasFee()) < 0 {
+		return nil, ErrTxGasFeeTooLow
 	}
 
 	// basic check tx
 	//  nonce not too far
 	account := tp.core.blockChain.LastBlock().stateTrie.GetAccount(*tx.from, false)
 	if account == nil {
-		log.Warn("ignore tx for non-exist account", "tx", tx)
-		// TODO: mark bad peer?
-		return
+		return nil, ErrTxNoAccount
 	}
 	currNonce := account.Nonce()
-	if (currNonce > tx.nonce) || (currNonce+TooFarTx < tx.nonce) {
-		log.Warn("tx nonce too far", "nonce", currNonce, "tx", tx)
-		// TODO: mark bad peer?
+	if currNonce > tx.nonce {
+		return nil, ErrTxNonceTooLow
+	}
+	if currNonce+TooFarTx < tx.nonce {
+		return nil, ErrTxNonceTooFar
+	}
+	if account.Balance().Cmp(tx.Cost()) < 0 {
+		return nil, ErrTxInsufficientBalance
+	}
+
+	from := *tx.from
+	old := tp.lookup(from, tx.nonce)
+	if old != nil {
+		// replace tx of same nonce only with higher fee
+		if tx.Fee().Cmp(old.Fee()) <= 0 {
+			return nil, ErrTxReplaceUnderpriced
+		}
+	} else {
+		if tp.accountLen(from) >= MaxAccountTxs {
+			return nil, ErrTxAccountFull
+		}
+		if len(tp.all) >= MaxPoolTxs {
+			if err := tp.evictFor(tx); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	tp.all[hash] = tx
+	tp.added[hash] = time.Now()
+	if list := tp.pending[from]; list != nil && list.Get(tx.nonce) != nil {
+		// replace executable tx in place
+		tp.forget(list.Put(tx))
+		return Transactions{tx}, nil
+	}
+	if old != nil {
+		tp.forget(tp.queue[from].Put(tx))
+	} else {
+		tp.queueTx(from, tx)
+	}
+	return tp.promote(from, currNonce), nil
+}
+
+// tx of account and nonce, in pending or queue
+func (tp *TransactionPool) lookup(from common.Address, nonce uint64) *Transaction {
+	if list := tp.pending[from]; list != nil {
+		if tx := list.Get(nonce); tx != nil {
+			return tx
+		}
+	}
+	if list := tp.queue[from]; list != nil {
+		return list.Get(nonce)
+	}
+	return nil
+}
+
+func (tp *TransactionPool) accountLen(from common.Address) int {
+	count := 0
+	if list := tp.pending[from]; list != nil {
+		count += list.Len()
+	}
+	if list := tp.queue[from]; list != nil {
+		count += list.Len()
+	}
+	return count
+}
+
+func (tp *TransactionPool) queueTx(from common.Address, tx *Transaction) {
+	list := tp.queue[from]
+	if list == nil {
+		list = newTxList()
+		tp.queue[from] = list
+	}
+	list.Put(tx)
+}
+
+// move queued txs of account to pending if nonce gap filled
+//   returns txs became executable
+func (tp *TransactionPool) promote(from common.Address, stateNonce uint64) Transactions {
+	queue := tp.queue[from]
+	if queue == nil {
+		return nil
+	}
+	pending := tp.pending[from]
+	next := stateNonce
+	if pending != nil {
+		if last := pending.Last(); last != nil {
+			next = last.nonce + 1
+		}
+	}
+	ready := queue.Ready(next)
+	if queue.Len() == 0 {
+		delete(tp.queue, from)
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+	if pending == nil {
+		pending = newTxList()
+		tp.pending[from] = pending
+	}
+	for _, tx := range ready {
+		pending.Put(tx)
+	}
+	return ready
+}
+
+// evict the cheapest tx for a new tx, when pool is full
+//   only the highest nonce tx of an account is evicted, to keep pending consecutive
+//   queued txs are evicted before pending txs
+func (tp *TransactionPool) evictFor(tx *Transaction) error {
+	victim := cheapestTail(tp.queue)
+	if victim == nil {
+		victim = cheapestTail(tp.pending)
+	}
+	if victim == nil || tx.Fee().Cmp(victim.Fee()) <= 0 {
+		return ErrTxUnderpriced
+	}
+	tp.removeTx(victim)
+	log.Debug("tx evicted from full pool", "tx", victim)
+	return nil
+}
+
+func cheapestTail(lists map[common.Address]*txList) *Transaction {
+	var cheapest *Transaction
+	for _, list := range lists {
+		last := list.Last()
+		if last == nil {
+			continue
+		}
+		if cheapest == nil || last.Fee().Cmp(cheapest.Fee()) < 0 {
+			cheapest = last
+		}
+	}
+	return cheapest
+}
+
+// remove a tx from pending or queue
+func (tp *TransactionPool) removeTx(tx *Transaction) {
+	from := *tx.from
+	for _, lists := range []map[common.Address]*txList{tp.pending, tp.queue} {
+		list := lists[from]
+		if list == nil || list.Get(tx.nonce) != tx {
+			continue
+		}
+		list.Remove(tx.nonce)
+		if list.Len() == 0 {
+			delete(lists, from)
+		}
+	}
+	tp.forget(tx)
+}
+
+func (tp *TransactionPool) forget(tx *Transaction) {
+	if tx == nil {
 		return
 	}
+	delete(tp.all, *tx.Hash())
+	delete(tp.added, *tx.Hash())
+}
 
-	// put tx to DHT
-	// TODO:
+// OnTxSealed is informed by chain of txs sealed in block at height
+//   sealed txs, and txs made stale by account nonce, are dropped from pool
+//   only senders of sealed txs are rechecked, nonce of other accounts unchanged
+func (tp *TransactionPool) OnTxSealed(height uint64, txs Transactions) {
+	accounts := make(map[common.Address]struct{}, len(txs))
+	for _, tx := range txs {
+		if tx.from != nil {
+			accounts[*tx.from] = struct{}{}
+		}
+	}
+	promoted := tp.reset(txs, accounts)
+	log.Trace("tx pool reset", "height", height, "sealed", len(txs), "promoted", len(promoted))
+	tp.sendToEngine(promoted)
+}
+
+// OnHeadReset is informed by chain of last block replaced, e.g. rewound or state synced
+//   all accounts in pool are rechecked against state of new last block
+func (tp *TransactionPool) OnHeadReset(height uint64) {
+	promoted := tp.reset(nil, nil)
+	log.Trace("tx pool reset", "height", height, "promoted", len(promoted))
+	tp.sendToEngine(promoted)
+}
+
+// OnTxsDropped is informed by chain of txs in blocks dropped by reorg
+//   txs not sealed again are added back to pool
+func (tp *TransactionPool) OnTxsDropped(txs Transactions) {
+	for _, tx := range txs {
+		if err := tp.verifyTx(tx); err != nil {
+			continue
+		}
+		switch err := tp.addTx(tx); err {
+		case nil, ErrTxKnown, ErrTxNonceTooLow:
+		default:
+			log.Debug("dropped tx not returned to pool", "err", err, "tx", tx)
+		}
+	}
+}
+
+// drop sealed and stale txs, promote txs became executable
+//   of given accounts, or all accounts in pool if nil
+func (tp *TransactionPool) reset(sealed Transactions, accounts map[common.Address]struct{}) Transactions {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	for _, tx := range sealed {
+		if tx, ok := tp.all[*tx.Hash()]; ok {
+			tp.removeTx(tx)
+		}
+	}
+
+	state := tp.core.blockChain.LastBlock().stateTrie
+	if accounts == nil {
+		accounts = make(map[common.Address]struct{})
+		for from := range tp.pending {
+			accounts[from] = struct{}{}
+		}
+		for from := range tp.queue {
+			accounts[from] = struct{}{}
+		}
+	}
+	var promoted Transactions
+	for from := range accounts {
+		var nonce uint64
+		if account := state.GetAccount(from, false); account != nil {
+			nonce = account.Nonce()
+		}
+		for _, lists := range []map[common.Address]*txList{tp.pending, tp.queue} {
+			list := lists[from]
+			if list == nil {
+				continue
+			}
+			for _, tx := range list.Forward(nonce) {
+				tp.forget(tx)
+			}
+			if list.Len() == 0 {
+				delete(lists, from)
+			}
+		}
+		// pending no longer starts from account nonce, demote to queue
+		if pending := tp.pending[from]; pending != nil && pending.First().nonce != nonce {
+			for _, tx := range pending.Flatten() {
+				tp.queueTx(from, tx)
+			}
+			delete(tp.pending, from)
+		}
+		promoted = append(promoted, tp.promote(from, nonce)...)
+	}
+	return promoted
+}
+
+// drop queued txs waiting longer than QueuedTxLifetime
+func (tp *TransactionPool) expireQueued(now time.Time) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
 
-	// send tx to consensus
-	if tp.core.engine != nil {
+	for _, list := range tp.queue {
+		for _, tx := range list.Flatten() {
+			if now.Sub(tp.added[*tx.Hash()]) > QueuedTxLifetime {
+				log.Debug("queued tx expired", "tx", tx)
+				tp.removeTx(tx)
+			}
+		}
+	}
+}
+
+func (tp *TransactionPool) sendToEngine(txs Transactions) {
+	if tp.core.engine == nil {
+		return
+	}
+	for _, tx := range txs {
 		tp.core.engine.SendTx(*tx.Hash())
 	}
 }
 
+// Get a tx in pool by hash, nil if not found
+func (tp *TransactionPool) Get(hash common.Hash) *Transaction {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+	return tp.all[hash]
+}
+
+// Stats returns number of pending and queued txs
+func (tp *TransactionPool) Stats() (pending int, queued int) {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+	for _, list := range tp.pending {
+		pending += list.Len()
+	}
+	for _, list := range tp.queue {
+		queued += list.Len()
+	}
+	return pending, queued
+}
+
+// Content returns pending and queued txs of each account, by nonce
+func (tp *TransactionPool) Content() (pending, queued map[common.Address]Transactions) {
+	tp.mu.RLock()
+	defer tp.mu.RUnlock()
+	pending = make(map[common.Address]Transactions, len(tp.pending))
+	for from, list := range tp.pending {
+		pending[from] = list.Flatten()
+	}
+	queued = make(map[common.Address]Transactions, len(tp.queue))
+	for from, list := range tp.queue {
+		queued[from] = list.Flatten()
+	}
+	return pending, queued
+}
+
 func (tp *TransactionPool) TxBroadcast(tx *Transaction) {
 	data, err := tx.Encode()
 	if err != nil {
@@ -189,7 +578,7 @@ func (tp *TransactionPool) TxBroadcast(tx *Transaction) {
 		}
 	}(p2p.Message{
 		MsgType: p2p.MessageTypeTx,
-		From:    "node1",
+		From:    tp.core.node.NodeID(),
 		Data:    data,
 	})
 }
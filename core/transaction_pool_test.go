@@ -0,0 +1,242 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/yeeco/gyee/core/yvm"
+	"github.com/yeeco/gyee/persistent"
+)
+
+func newTestTxPool(t *testing.T, tv *testValidators) (*TransactionPool, *BlockChain) {
+	storage := persistent.NewMemoryStorage()
+	chain := tv.newChain(t, storage)
+	tp, err := NewTransactionPool(&Core{blockChain: chain, storage: storage})
+	if err != nil {
+		t.Fatalf("NewTransactionPool() %v", err)
+	}
+	chain.txPool = tp
+	return tp, chain
+}
+
+func (tv *testValidators) newPoolTx(from int, nonce uint64, fee int64) *Transaction {
+	tx := NewTransactionWithFee(uint32(TestNetID), nonce, &tv.addrs[1], big.NewInt(1), big.NewInt(fee))
+	tx.from = &tv.addrs[from]
+	return tx
+}
+
+func checkPoolStats(t *testing.T, tp *TransactionPool, pending, queued int) {
+	t.Helper()
+	if p, q := tp.Stats(); p != pending || q != queued {
+		t.Fatalf("pool stats, need %d/%d got %d/%d", pending, queued, p, q)
+	}
+}
+
+func TestTxPoolQueuePromote(t *testing.T) {
+	tv := newTestValidators(t, 2)
+	tp, _ := newTestTxPool(t, tv)
+
+	// future txs wait in queue
+	for _, nonce := range []uint64{2, 1} {
+		if err := tp.addTx(tv.newPoolTx(0, nonce, 1)); err != nil {
+			t.Fatalf("addTx(%d) %v", nonce, err)
+		}
+	}
+	checkPoolStats(t, tp, 0, 2)
+
+	// gap filled, all executable
+	tx0 := tv.newPoolTx(0, 0, 1)
+	if err := tp.addTx(tx0); err != nil {
+		t.Fatalf("addTx(0) %v", err)
+	}
+	checkPoolStats(t, tp, 3, 0)
+	pending, _ := tp.Content()
+	for i, tx := range pending[tv.addrs[0]] {
+		if tx.nonce != uint64(i) {
+			t.Fatalf("pending not ordered by nonce, %v", pending[tv.addrs[0]])
+		}
+	}
+	if tp.Get(*tx0.Hash()) != tx0 {
+		t.Fatalf("Get() tx not found")
+	}
+
+	// rejections
+	if err := tp.addTx(tx0); err != ErrTxKnown {
+		t.Errorf("known tx, got %v", err)
+	}
+	if err := tp.addTx(tv.newPoolTx(0, 1, 0)); err != ErrTxReplaceUnderpriced {
+		t.Errorf("underpriced replacement, got %v", err)
+	}
+	if err := tp.addTx(tv.newPoolTx(0, TooFarTx+1, 1)); err != ErrTxNonceTooFar {
+		t.Errorf("nonce too far, got %v", err)
+	}
+	rich := NewTransactionWithFee(uint32(TestNetID), 3, &tv.addrs[1], big.NewInt(1e10), nil)
+	rich.from = &tv.addrs[0]
+	if err := tp.addTx(rich); err != ErrTxInsufficientBalance {
+		t.Errorf("insufficient balance, got %v", err)
+	}
+
+	// replacement with higher fee keeps tx executable
+	replace := tv.newPoolTx(0, 1, 2)
+	if err := tp.addTx(replace); err != nil {
+		t.Fatalf("replacement %v", err)
+	}
+	checkPoolStats(t, tp, 3, 0)
+	pending, _ = tp.Content()
+	if pending[tv.addrs[0]][1] != replace {
+		t.Errorf("tx not replaced")
+	}
+}
+
+func TestTxPoolMinFee(t *testing.T) {
+	tv := newTestValidators(t, 2)
+	tp, _ := newTestTxPool(t, tv)
+	tp.minFee.SetInt64(5)
+
+	if err := tp.addTx(tv.newPoolTx(0, 0, 4)); err != ErrTxFeeTooLow {
+		t.Errorf("fee too low, got %v", err)
+	}
+	if err := tp.addTx(tv.newPoolTx(0, 0, 5)); err != nil {
+		t.Errorf("min fee tx, got %v", err)
+	}
+}
+
+func TestTxPoolGasFee(t *testing.T) {
+	tv := newTestValidators(t, 2)
+	tp, _ := newTestTxPool(t, tv)
+	newCallTx := func(fee int64, gasLimit uint64) *Transaction {
+		tx := NewCallTransaction(uint32(TestNetID), 0, &tv.addrs[1], nil, new(big.Int), big.NewInt(fee), gasLimit)
+		tx.from = &tv.addrs[0]
+		return tx
+	}
+
+	if err := tp.addTx(newCallTx(999*GasPrice, 1000)); err != ErrTxGasFeeTooLow {
+		t.Errorf("fee not covering gas, got %v", err)
+	}
+	if err := tp.addTx(newCallTx(yvm.MaxGasLimit*GasPrice+1, yvm.MaxGasLimit+1)); err != ErrTxGasLimitTooHigh {
+		t.Errorf("gas limit too high, got %v", err)
+	}
+	if err := tp.addTx(newCallTx(1000*GasPrice, 1000)); err != nil {
+		t.Errorf("fee covering gas, got %v", err)
+	}
+}
+
+func TestTxPoolLimits(t *testing.T) {
+	tv := newTestValidators(t, 2)
+	tp, _ := newTestTxPool(t, tv)
+
+	for nonce := uint64(0); nonce < MaxAccountTxs; nonce++ {
+		if err := tp.addTx(tv.newPoolTx(0, nonce+1, 1)); err != nil {
+			t.Fatalf("addTx(%d) %v", nonce, err)
+		}
+	}
+	if err := tp.addTx(tv.newPoolTx(0, 0, 1)); err != ErrTxAccountFull {
+		t.Errorf("account full, got %v", err)
+	}
+
+	// evict cheapest account tail, queued before pending
+	pendingTx := tv.newPoolTx(1, 0, 1)
+	if err := tp.addTx(pendingTx); err != nil {
+		t.Fatalf("addTx() %v", err)
+	}
+	if err := tp.evictFor(tv.newPoolTx(1, 1, 1)); err != ErrTxUnderpriced {
+		t.Errorf("evict for same fee, got %v", err)
+	}
+	if err := tp.evictFor(tv.newPoolTx(1, 1, 2)); err != nil {
+		t.Fatalf("evictFor() %v", err)
+	}
+	checkPoolStats(t, tp, 1, MaxAccountTxs-1)
+	_, queued := tp.Content()
+	if last := queued[tv.addrs[0]][MaxAccountTxs-2]; last.nonce != MaxAccountTxs-1 {
+		t.Errorf("evicted tx not account tail, last nonce %d", last.nonce)
+	}
+}
+
+func TestTxPoolSealedAndExpired(t *testing.T) {
+	tv := newTestValidators(t, 2)
+	tp, chain := newTestTxPool(t, tv)
+
+	var txs Transactions
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		tx := tv.newTx(t, 0, 1, nonce, 1)
+		if err := tp.addTx(tx); err != nil {
+			t.Fatalf("addTx(%d) %v", nonce, err)
+		}
+		txs = append(txs, tx)
+	}
+	future := tv.newPoolTx(0, 5, 1)
+	if err := tp.addTx(future); err != nil {
+		t.Fatalf("addTx(future) %v", err)
+	}
+	// tx of another account, not sealed
+	untouched := tv.newTx(t, 1, 0, 0, 1)
+	if err := tp.addTx(untouched); err != nil {
+		t.Fatalf("addTx(untouched) %v", err)
+	}
+	// a different tx of nonce 2 from the same account sealed elsewhere
+	other := tv.newTx(t, 0, 1, 2, 2)
+	checkPoolStats(t, tp, 4, 1)
+
+	b := tv.nextBlock(t, chain, chain.LastBlock(), Transactions{txs[0], txs[1], other})
+	if err := chain.AddBlock(b); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+	checkPoolStats(t, tp, 1, 1)
+	if tp.Get(*txs[2].Hash()) != nil {
+		t.Errorf("stale tx kept in pool")
+	}
+	if pending, _ := tp.Content(); len(pending[tv.addrs[1]]) != 1 {
+		t.Errorf("pending tx of other account dropped")
+	}
+
+	tp.expireQueued(time.Now())
+	checkPoolStats(t, tp, 1, 1)
+	tp.expireQueued(time.Now().Add(QueuedTxLifetime + time.Second))
+	checkPoolStats(t, tp, 1, 0)
+}
+
+func TestTxPoolReorg(t *testing.T) {
+	tv := newTestValidators(t, 2)
+	tp, chain := newTestTxPool(t, tv)
+	tv.growChain(t, chain, 1)
+
+	kept := tv.newTx(t, 1, 0, 0, 1)
+	dropped := tv.newTx(t, 0, 1, 1, 1)
+	b2 := tv.nextBlock(t, chain, chain.LastBlock(), Transactions{kept, dropped})
+	if err := chain.AddBlock(b2); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+	tv.growChain(t, chain, 1)
+	next := tv.newTx(t, 0, 1, 2, 1) // sealed in block 3 by growChain
+	checkPoolStats(t, tp, 0, 0)
+
+	// fork at height 2 seals one of the txs, others of dropped blocks return to pool
+	fork := tv.nextBlock(t, chain, chain.GetBlockByNumber(1), Transactions{kept})
+	if err := chain.Reorg(fork); err != nil {
+		t.Fatalf("Reorg() %v", err)
+	}
+	checkPoolStats(t, tp, 2, 0)
+	for _, tx := range []*Transaction{dropped, next} {
+		if tp.Get(*tx.Hash()) == nil {
+			t.Errorf("tx of dropped block not in pool, nonce %d", tx.nonce)
+		}
+	}
+}
@@ -83,3 +83,29 @@ func TestTxEncode(t *testing.T) {
 		t.Errorf("tx encoded hex mismatch, got %v", hexStr)
 	}
 }
+
+func TestTxFee(t *testing.T) {
+	address := common.HexToAddress(txTestAddress)
+	tx := NewTransactionWithFee(255, 128, &address, big.NewInt(10000), big.NewInt(20))
+	if tx.Fee().Cmp(big.NewInt(20)) != 0 {
+		t.Errorf("wrong fee")
+	}
+	if tx.Cost().Cmp(big.NewInt(10020)) != 0 {
+		t.Errorf("wrong cost %v", tx.Cost())
+	}
+	noFee := NewTransaction(255, 128, &address, big.NewInt(10000))
+	if *tx.Hash() == *noFee.Hash() {
+		t.Errorf("fee not covered by tx hash")
+	}
+	enc, err := tx.Encode()
+	if err != nil {
+		t.Fatalf("tx Encode failed %v", err)
+	}
+	decoded := new(Transaction)
+	if err := decoded.Decode(enc); err != nil {
+		t.Fatalf("tx Decode failed %v", err)
+	}
+	if decoded.Fee().Cmp(tx.Fee()) != 0 || *decoded.Hash() != *tx.Hash() {
+		t.Errorf("decoded tx mismatch, got %v", decoded)
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"sort"
+)
+
+// txList holds txs of a single account, indexed by nonce
+type txList struct {
+	items map[uint64]*Transaction
+}
+
+func newTxList() *txList {
+	return &txList{items: make(map[uint64]*Transaction)}
+}
+
+func (l *txList) Len() int { return len(l.items) }
+
+func (l *txList) Get(nonce uint64) *Transaction { return l.items[nonce] }
+
+// put tx to list, returns replaced tx of same nonce if any
+func (l *txList) Put(tx *Transaction) *Transaction {
+	old := l.items[tx.nonce]
+	l.items[tx.nonce] = tx
+	return old
+}
+
+func (l *txList) Remove(nonce uint64) *Transaction {
+	tx, ok := l.items[nonce]
+	if !ok {
+		return nil
+	}
+	delete(l.items, nonce)
+	return tx
+}
+
+// remove and return txs with nonce lower than threshold
+func (l *txList) Forward(threshold uint64) Transactions {
+	var removed Transactions
+	for nonce, tx := range l.items {
+		if nonce < threshold {
+			removed = append(removed, tx)
+			delete(l.items, nonce)
+		}
+	}
+	return removed
+}
+
+// remove and return txs with consecutive nonce from start
+func (l *txList) Ready(start uint64) Transactions {
+	var ready Transactions
+	for nonce := start; ; nonce++ {
+		tx, ok := l.items[nonce]
+		if !ok {
+			break
+		}
+		ready = append(ready, tx)
+		delete(l.items, nonce)
+	}
+	return ready
+}
+
+// tx with lowest / highest nonce, nil if empty
+func (l *txList) First() *Transaction {
+	txs := l.Flatten()
+	if len(txs) == 0 {
+		return nil
+	}
+	return txs[0]
+}
+
+func (l *txList) Last() *Transaction {
+	txs := l.Flatten()
+	if len(txs) == 0 {
+		return nil
+	}
+	return txs[len(txs)-1]
+}
+
+// txs sorted by nonce
+func (l *txList) Flatten() Transactions {
+	txs := make(Transactions, 0, len(l.items))
+	for _, tx := range l.items {
+		txs = append(txs, tx)
+	}
+	sort.Slice(txs, func(i, j int) bool { return txs[i].nonce < txs[j].nonce })
+	return txs
+}
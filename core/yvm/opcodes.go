@@ -0,0 +1,145 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package yvm
+
+import "fmt"
+
+// OpCode is a single byte instruction of contract code.
+// Operands are 256 bits unsigned words on stack, arithmetic wraps around 2^256.
+type OpCode byte
+
+const (
+	// halt, returning nothing
+	STOP OpCode = 0x00
+
+	// arithmetic / logic, pop operands and push result
+	//   a = top, b = next, e.g. SUB pushes a - b, LT pushes 1 if a < b
+	//   DIV / MOD by zero push 0
+	ADD    OpCode = 0x01
+	SUB    OpCode = 0x02
+	MUL    OpCode = 0x03
+	DIV    OpCode = 0x04
+	MOD    OpCode = 0x05
+	LT     OpCode = 0x06
+	GT     OpCode = 0x07
+	EQ     OpCode = 0x08
+	ISZERO OpCode = 0x09
+	AND    OpCode = 0x0a
+	OR     OpCode = 0x0b
+	XOR    OpCode = 0x0c
+	NOT    OpCode = 0x0d
+
+	// stack, with one byte immediate n for DUP / SWAP / PUSH
+	//   DUP n:  push copy of n-th item, 1 for top
+	//   SWAP n: swap top with (n+1)-th item
+	//   PUSH n: push next n (1..32) bytes as big endian word
+	POP  OpCode = 0x10
+	DUP  OpCode = 0x11
+	SWAP OpCode = 0x12
+	PUSH OpCode = 0x13
+
+	// control flow, jump target must be a JUMPDEST
+	//   JUMP:  pop target
+	//   JUMPI: pop target, cond, jump if cond != 0
+	JUMP     OpCode = 0x20
+	JUMPI    OpCode = 0x21
+	JUMPDEST OpCode = 0x22
+
+	// environment
+	//   CALLDATALOAD: pop offset, push 32 bytes of input from offset, zero padded
+	//   BALANCE: pop address, push its balance
+	CALLER       OpCode = 0x30
+	ADDRESS      OpCode = 0x31
+	CALLVALUE    OpCode = 0x32
+	CALLDATALOAD OpCode = 0x33
+	CALLDATASIZE OpCode = 0x34
+	BALANCE      OpCode = 0x35
+	NUMBER       OpCode = 0x36
+
+	// contract account state
+	//   SLOAD:    pop key, push value
+	//   SSTORE:   pop key, value
+	//   TRANSFER: pop address, amount, send from contract balance
+	SLOAD    OpCode = 0x40
+	SSTORE   OpCode = 0x41
+	TRANSFER OpCode = 0x42
+
+	// halt, RETURN pops a word as 32 bytes output
+	//   REVERT discards all state changes of the call
+	RETURN OpCode = 0x50
+	REVERT OpCode = 0x51
+)
+
+var opCodeNames = map[OpCode]string{
+	STOP: "STOP", ADD: "ADD", SUB: "SUB", MUL: "MUL", DIV: "DIV", MOD: "MOD",
+	LT: "LT", GT: "GT", EQ: "EQ", ISZERO: "ISZERO",
+	AND: "AND", OR: "OR", XOR: "XOR", NOT: "NOT",
+	POP: "POP", DUP: "DUP", SWAP: "SWAP", PUSH: "PUSH",
+	JUMP: "JUMP", JUMPI: "JUMPI", JUMPDEST: "JUMPDEST",
+	CALLER: "CALLER", ADDRESS: "ADDRESS", CALLVALUE: "CALLVALUE",
+	CALLDATALOAD: "CALLDATALOAD", CALLDATASIZE: "CALLDATASIZE",
+	BALANCE: "BALANCE", NUMBER: "NUMBER",
+	SLOAD: "SLOAD", SSTORE: "SSTORE", TRANSFER: "TRANSFER",
+	RETURN: "RETURN", REVERT: "REVERT",
+}
+
+func (op OpCode) String() string {
+	if name, ok := opCodeNames[op]; ok {
+		return name
+	}
+	return fmt.Sprintf("opcode(0x%02x)", byte(op))
+}
+
+// bytes of immediate operand following op
+func (op OpCode) immediateSize(next byte) int {
+	switch op {
+	case DUP, SWAP:
+		return 1
+	case PUSH:
+		return 1 + int(next)
+	default:
+		return 0
+	}
+}
+
+// gas cost of executing a contract
+const (
+	GasQuick    = 1    // arithmetic / stack ops
+	GasJump     = 8    // JUMP / JUMPI
+	GasEnv      = 2    // environment reads
+	GasBalance  = 100  // BALANCE
+	GasSLoad    = 50   // SLOAD
+	GasSSet     = 500  // SSTORE setting a zero slot to non-zero
+	GasSReset   = 100  // other SSTORE
+	GasTransfer = 200  // TRANSFER
+	GasCall     = 100  // base cost of a call
+	GasDeploy   = 1000 // base cost of deploying a contract
+	GasCodeByte = 10   // per byte of deployed code
+)
+
+var opGas = map[OpCode]uint64{
+	STOP: 0, ADD: GasQuick, SUB: GasQuick, MUL: GasQuick * 3, DIV: GasQuick * 3, MOD: GasQuick * 3,
+	LT: GasQuick, GT: GasQuick, EQ: GasQuick, ISZERO: GasQuick,
+	AND: GasQuick, OR: GasQuick, XOR: GasQuick, NOT: GasQuick,
+	POP: GasQuick, DUP: GasQuick, SWAP: GasQuick, PUSH: GasQuick,
+	JUMP: GasJump, JUMPI: GasJump, JUMPDEST: GasQuick,
+	CALLER: GasEnv, ADDRESS: GasEnv, CALLVALUE: GasEnv,
+	CALLDATALOAD: GasEnv, CALLDATASIZE: GasEnv, BALANCE: GasBalance, NUMBER: GasEnv,
+	SLOAD: GasSLoad, SSTORE: 0, TRANSFER: GasTransfer, // SSTORE charged by slot
+	RETURN: 0, REVERT: 0,
+}
@@ -0,0 +1,121 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package yvm
+
+import (
+	"math/big"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/core/state"
+)
+
+// pending state changes of a contract tx, over account trie
+//   written to account trie only if execution succeeded
+type pendingState struct {
+	state    state.AccountTrie
+	balances map[common.Address]*big.Int
+	storage  map[common.Address]map[common.Hash]common.Hash
+	code     map[common.Address][]byte
+}
+
+func newPendingState(st state.AccountTrie) *pendingState {
+	return &pendingState{
+		state:    st,
+		balances: make(map[common.Address]*big.Int),
+		storage:  make(map[common.Address]map[common.Hash]common.Hash),
+		code:     make(map[common.Address][]byte),
+	}
+}
+
+func (ps *pendingState) balance(addr common.Address) *big.Int {
+	if balance, ok := ps.balances[addr]; ok {
+		return balance
+	}
+	balance := new(big.Int)
+	if account := ps.state.GetAccount(addr, false); account != nil {
+		balance.Set(account.Balance())
+	}
+	ps.balances[addr] = balance
+	return balance
+}
+
+func (ps *pendingState) transfer(from, to common.Address, amount *big.Int) error {
+	if amount.Sign() == 0 {
+		return nil
+	}
+	fromBalance := ps.balance(from)
+	if fromBalance.Cmp(amount) < 0 {
+		return ErrInsufficientBalance
+	}
+	fromBalance.Sub(fromBalance, amount)
+	toBalance := ps.balance(to)
+	toBalance.Add(toBalance, amount)
+	return nil
+}
+
+func (ps *pendingState) getCode(addr common.Address) []byte {
+	if code, ok := ps.code[addr]; ok {
+		return code
+	}
+	if account := ps.state.GetAccount(addr, false); account != nil {
+		return account.Code()
+	}
+	return nil
+}
+
+func (ps *pendingState) setCode(addr common.Address, code []byte) {
+	ps.code[addr] = code
+}
+
+func (ps *pendingState) getStorage(addr common.Address, key common.Hash) common.Hash {
+	if value, ok := ps.storage[addr][key]; ok {
+		return value
+	}
+	if account := ps.state.GetAccount(addr, false); account != nil {
+		return account.GetStorage(key)
+	}
+	return common.Hash{}
+}
+
+func (ps *pendingState) setStorage(addr common.Address, key, value common.Hash) {
+	slots := ps.storage[addr]
+	if slots == nil {
+		slots = make(map[common.Hash]common.Hash)
+		ps.storage[addr] = slots
+	}
+	slots[key] = value
+}
+
+// write changes to account trie
+func (ps *pendingState) commit() {
+	for addr, code := range ps.code {
+		ps.state.GetAccount(addr, true).SetCode(code)
+	}
+	for addr, balance := range ps.balances {
+		account := ps.state.GetAccount(addr, balance.Sign() > 0)
+		if account != nil {
+			account.SetBalance(balance)
+		}
+	}
+	for addr, slots := range ps.storage {
+		account := ps.state.GetAccount(addr, true)
+		for key, value := range slots {
+			account.SetStorage(key, value)
+		}
+	}
+}
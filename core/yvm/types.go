@@ -20,5 +20,68 @@
 
 package yvm
 
+import (
+	"encoding/binary"
+	"errors"
+	"math/big"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/common/address"
+	"github.com/yeeco/gyee/core/state"
+)
+
+const (
+	MaxCodeSize = 24 * 1024 // max bytes of contract code
+	MaxGasLimit = 10000000  // max gas of a single contract tx
+	StackLimit  = 1024      // max items on stack
+)
+
+var (
+	ErrOutOfGas            = errors.New("yvm: out of gas")
+	ErrStackUnderflow      = errors.New("yvm: stack underflow")
+	ErrStackOverflow       = errors.New("yvm: stack overflow")
+	ErrInvalidOpCode       = errors.New("yvm: invalid opcode")
+	ErrInvalidJump         = errors.New("yvm: invalid jump destination")
+	ErrExecutionReverted   = errors.New("yvm: execution reverted")
+	ErrInsufficientBalance = errors.New("yvm: insufficient balance")
+	ErrCodeEmpty           = errors.New("yvm: empty contract code")
+	ErrCodeTooLarge        = errors.New("yvm: contract code too large")
+	ErrContractExists      = errors.New("yvm: contract address exists")
+	ErrNoContractCode      = errors.New("yvm: no contract code at address")
+)
+
+// YVM executes contracts against account state, deterministic for the same state and inputs.
+// State is only changed if execution succeeded.
 type YVM interface {
+	// Deploy contract code with value transferred from caller,
+	//   returns contract address and gas used
+	Deploy(ctx *Context, code []byte, value *big.Int) (common.Address, uint64, error)
+
+	// Call contract with input and value transferred from caller,
+	//   returns output and gas used
+	Call(ctx *Context, contract common.Address, input []byte, value *big.Int) ([]byte, uint64, error)
+}
+
+// Context of a contract tx
+type Context struct {
+	State    state.AccountTrie
+	ChainID  uint32 // chain of tx, separating contract addresses of chains
+	Caller   common.Address
+	Nonce    uint64 // caller nonce of tx, deriving contract address
+	Number   uint64 // block number
+	GasLimit uint64
+}
+
+// ContractAddress derives address of contract deployed on chain by caller with tx nonce
+//   chain id hashed with caller and nonce, addresses differ among chains
+func ContractAddress(chainID uint32, caller common.Address, nonce uint64) (common.Address, error) {
+	data := make([]byte, 4+common.AddressLength+8)
+	binary.BigEndian.PutUint32(data, chainID)
+	copy(data[4:], caller[:])
+	binary.BigEndian.PutUint64(data[4+common.AddressLength:], nonce)
+	addr, err := address.NewContractAddressFromData(data)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return *addr.CommonAddress(), nil
 }
@@ -0,0 +1,406 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package yvm
+
+import (
+	"math/big"
+
+	"github.com/yeeco/gyee/common"
+)
+
+var (
+	tt256   = new(big.Int).Lsh(big.NewInt(1), 256)
+	tt256m1 = new(big.Int).Sub(tt256, big.NewInt(1))
+)
+
+// stack based interpreter of contract code
+type interpreter struct{}
+
+func NewYVM() YVM {
+	return &interpreter{}
+}
+
+func (vm *interpreter) Deploy(ctx *Context, code []byte, value *big.Int) (common.Address, uint64, error) {
+	gasLimit := ctx.gasLimit()
+	if len(code) == 0 {
+		return common.Address{}, gasLimit, ErrCodeEmpty
+	}
+	if len(code) > MaxCodeSize {
+		return common.Address{}, gasLimit, ErrCodeTooLarge
+	}
+	gas := uint64(GasDeploy + GasCodeByte*len(code))
+	if gas > gasLimit {
+		return common.Address{}, gasLimit, ErrOutOfGas
+	}
+	contract, err := ContractAddress(ctx.ChainID, ctx.Caller, ctx.Nonce)
+	if err != nil {
+		return common.Address{}, gasLimit, err
+	}
+	if account := ctx.State.GetAccount(contract, false); account != nil && len(account.Code()) > 0 {
+		return contract, gasLimit, ErrContractExists
+	}
+	ps := newPendingState(ctx.State)
+	if err := ps.transfer(ctx.Caller, contract, value); err != nil {
+		return contract, gas, err
+	}
+	ps.setCode(contract, common.CopyBytes(code))
+	ps.commit()
+	return contract, gas, nil
+}
+
+func (vm *interpreter) Call(ctx *Context, contract common.Address, input []byte, value *big.Int) ([]byte, uint64, error) {
+	gasLimit := ctx.gasLimit()
+	if gasLimit < GasCall {
+		return nil, gasLimit, ErrOutOfGas
+	}
+	ps := newPendingState(ctx.State)
+	code := ps.getCode(contract)
+	if len(code) == 0 {
+		return nil, GasCall, ErrNoContractCode
+	}
+	if err := ps.transfer(ctx.Caller, contract, value); err != nil {
+		return nil, GasCall, err
+	}
+	f := &frame{
+		ctx:      ctx,
+		state:    ps,
+		contract: contract,
+		code:     code,
+		input:    input,
+		value:    value,
+		gasLeft:  gasLimit - GasCall,
+		stack:    make([]*big.Int, 0, 16),
+	}
+	output, err := f.run()
+	gasUsed := gasLimit - f.gasLeft
+	switch err {
+	case nil:
+		ps.commit()
+		return output, gasUsed, nil
+	case ErrExecutionReverted:
+		return nil, gasUsed, err
+	default:
+		// exceptional halt consumes all gas
+		return nil, gasLimit, err
+	}
+}
+
+func (ctx *Context) gasLimit() uint64 {
+	if ctx.GasLimit > MaxGasLimit {
+		return MaxGasLimit
+	}
+	return ctx.GasLimit
+}
+
+// execution of a contract call
+type frame struct {
+	ctx      *Context
+	state    *pendingState
+	contract common.Address
+	code     []byte
+	input    []byte
+	value    *big.Int
+
+	pc        int
+	gasLeft   uint64
+	stack     []*big.Int
+	jumpDests map[int]bool
+}
+
+func (f *frame) useGas(gas uint64) error {
+	if f.gasLeft < gas {
+		f.gasLeft = 0
+		return ErrOutOfGas
+	}
+	f.gasLeft -= gas
+	return nil
+}
+
+func (f *frame) push(v *big.Int) error {
+	if len(f.stack) >= StackLimit {
+		return ErrStackOverflow
+	}
+	f.stack = append(f.stack, v)
+	return nil
+}
+
+func (f *frame) pop() (*big.Int, error) {
+	if len(f.stack) == 0 {
+		return nil, ErrStackUnderflow
+	}
+	v := f.stack[len(f.stack)-1]
+	f.stack = f.stack[:len(f.stack)-1]
+	return v, nil
+}
+
+// pop a, b with a on top
+func (f *frame) pop2() (*big.Int, *big.Int, error) {
+	a, err := f.pop()
+	if err != nil {
+		return nil, nil, err
+	}
+	b, err := f.pop()
+	if err != nil {
+		return nil, nil, err
+	}
+	return a, b, nil
+}
+
+// immediate byte following current op
+func (f *frame) immediate() (int, error) {
+	if f.pc+1 >= len(f.code) {
+		return 0, ErrInvalidOpCode
+	}
+	return int(f.code[f.pc+1]), nil
+}
+
+// JUMPDEST positions, skipping immediate operands
+func (f *frame) validJumpDest(dest *big.Int) bool {
+	if f.jumpDests == nil {
+		f.jumpDests = make(map[int]bool)
+		for pc := 0; pc < len(f.code); pc++ {
+			op := OpCode(f.code[pc])
+			if op == JUMPDEST {
+				f.jumpDests[pc] = true
+			}
+			next := byte(0)
+			if pc+1 < len(f.code) {
+				next = f.code[pc+1]
+			}
+			pc += op.immediateSize(next)
+		}
+	}
+	return dest.IsInt64() && f.jumpDests[int(dest.Int64())]
+}
+
+func boolWord(b bool) *big.Int {
+	if b {
+		return big.NewInt(1)
+	}
+	return new(big.Int)
+}
+
+func addressWord(addr common.Address) *big.Int {
+	return new(big.Int).SetBytes(addr[:])
+}
+
+func wordAddress(w *big.Int) common.Address {
+	return common.BytesToAddress(w.Bytes())
+}
+
+func wordHash(w *big.Int) common.Hash {
+	return common.BytesToHash(w.Bytes())
+}
+
+func (f *frame) run() ([]byte, error) {
+	for f.pc < len(f.code) {
+		op := OpCode(f.code[f.pc])
+		gas, ok := opGas[op]
+		if !ok {
+			return nil, ErrInvalidOpCode
+		}
+		if err := f.useGas(gas); err != nil {
+			return nil, err
+		}
+		next := f.pc + 1
+		var err error
+		switch op {
+		case STOP:
+			return nil, nil
+
+		case ADD, SUB, MUL, DIV, MOD, LT, GT, EQ, AND, OR, XOR:
+			var a, b *big.Int
+			if a, b, err = f.pop2(); err != nil {
+				return nil, err
+			}
+			r := new(big.Int)
+			switch op {
+			case ADD:
+				r.Add(a, b).And(r, tt256m1)
+			case SUB:
+				r.Sub(a, b).And(r, tt256m1)
+			case MUL:
+				r.Mul(a, b).And(r, tt256m1)
+			case DIV:
+				if b.Sign() != 0 {
+					r.Div(a, b)
+				}
+			case MOD:
+				if b.Sign() != 0 {
+					r.Mod(a, b)
+				}
+			case LT:
+				r = boolWord(a.Cmp(b) < 0)
+			case GT:
+				r = boolWord(a.Cmp(b) > 0)
+			case EQ:
+				r = boolWord(a.Cmp(b) == 0)
+			case AND:
+				r.And(a, b)
+			case OR:
+				r.Or(a, b)
+			case XOR:
+				r.Xor(a, b)
+			}
+			err = f.push(r)
+
+		case ISZERO, NOT:
+			var a *big.Int
+			if a, err = f.pop(); err != nil {
+				return nil, err
+			}
+			if op == ISZERO {
+				err = f.push(boolWord(a.Sign() == 0))
+			} else {
+				err = f.push(new(big.Int).Xor(a, tt256m1))
+			}
+
+		case POP:
+			_, err = f.pop()
+
+		case DUP, SWAP:
+			var n int
+			if n, err = f.immediate(); err != nil {
+				return nil, err
+			}
+			next++
+			if op == DUP {
+				if n == 0 || n > len(f.stack) {
+					return nil, ErrStackUnderflow
+				}
+				err = f.push(new(big.Int).Set(f.stack[len(f.stack)-n]))
+			} else {
+				if n == 0 || n+1 > len(f.stack) {
+					return nil, ErrStackUnderflow
+				}
+				top := len(f.stack) - 1
+				f.stack[top], f.stack[top-n] = f.stack[top-n], f.stack[top]
+			}
+
+		case PUSH:
+			var n int
+			if n, err = f.immediate(); err != nil {
+				return nil, err
+			}
+			start := f.pc + 2
+			if n == 0 || n > 32 || start+n > len(f.code) {
+				return nil, ErrInvalidOpCode
+			}
+			next = start + n
+			err = f.push(new(big.Int).SetBytes(f.code[start:next]))
+
+		case JUMP:
+			var dest *big.Int
+			if dest, err = f.pop(); err != nil {
+				return nil, err
+			}
+			if !f.validJumpDest(dest) {
+				return nil, ErrInvalidJump
+			}
+			next = int(dest.Int64())
+
+		case JUMPI:
+			var dest, cond *big.Int
+			if dest, cond, err = f.pop2(); err != nil {
+				return nil, err
+			}
+			if cond.Sign() != 0 {
+				if !f.validJumpDest(dest) {
+					return nil, ErrInvalidJump
+				}
+				next = int(dest.Int64())
+			}
+
+		case JUMPDEST:
+
+		case CALLER:
+			err = f.push(addressWord(f.ctx.Caller))
+		case ADDRESS:
+			err = f.push(addressWord(f.contract))
+		case CALLVALUE:
+			err = f.push(new(big.Int).Set(f.value))
+		case CALLDATASIZE:
+			err = f.push(big.NewInt(int64(len(f.input))))
+		case NUMBER:
+			err = f.push(new(big.Int).SetUint64(f.ctx.Number))
+
+		case CALLDATALOAD:
+			var offset *big.Int
+			if offset, err = f.pop(); err != nil {
+				return nil, err
+			}
+			word := make([]byte, 32)
+			if offset.IsInt64() && offset.Int64() < int64(len(f.input)) {
+				copy(word, f.input[offset.Int64():])
+			}
+			err = f.push(new(big.Int).SetBytes(word))
+
+		case BALANCE:
+			var addr *big.Int
+			if addr, err = f.pop(); err != nil {
+				return nil, err
+			}
+			err = f.push(new(big.Int).Set(f.state.balance(wordAddress(addr))))
+
+		case SLOAD:
+			var key *big.Int
+			if key, err = f.pop(); err != nil {
+				return nil, err
+			}
+			value := f.state.getStorage(f.contract, wordHash(key))
+			err = f.push(new(big.Int).SetBytes(value[:]))
+
+		case SSTORE:
+			var key, value *big.Int
+			if key, value, err = f.pop2(); err != nil {
+				return nil, err
+			}
+			slot := wordHash(key)
+			gas := uint64(GasSReset)
+			if f.state.getStorage(f.contract, slot) == (common.Hash{}) && value.Sign() != 0 {
+				gas = GasSSet
+			}
+			if err = f.useGas(gas); err != nil {
+				return nil, err
+			}
+			f.state.setStorage(f.contract, slot, wordHash(value))
+
+		case TRANSFER:
+			var to, amount *big.Int
+			if to, amount, err = f.pop2(); err != nil {
+				return nil, err
+			}
+			err = f.state.transfer(f.contract, wordAddress(to), amount)
+
+		case RETURN:
+			var v *big.Int
+			if v, err = f.pop(); err != nil {
+				return nil, err
+			}
+			return wordHash(v).Bytes(), nil
+
+		case REVERT:
+			return nil, ErrExecutionReverted
+		}
+		if err != nil {
+			return nil, err
+		}
+		f.pc = next
+	}
+	return nil, nil
+}
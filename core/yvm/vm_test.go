@@ -0,0 +1,180 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package yvm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/common/address"
+	"github.com/yeeco/gyee/core/state"
+	"github.com/yeeco/gyee/persistent"
+)
+
+// storage[0] += input word 0, returning the sum
+var counterCode = []byte{
+	byte(PUSH), 1, 0, byte(SLOAD),
+	byte(PUSH), 1, 0, byte(CALLDATALOAD),
+	byte(ADD),
+	byte(DUP), 1,
+	byte(PUSH), 1, 0, byte(SSTORE),
+	byte(RETURN),
+}
+
+func newTestContext(t *testing.T, balance int64) *Context {
+	st, err := state.NewAccountTrie(common.EmptyHash, state.NewDatabase(persistent.NewMemoryStorage()))
+	if err != nil {
+		t.Fatalf("NewAccountTrie() %v", err)
+	}
+	caller := common.BytesToAddress([]byte{0xca})
+	st.GetAccount(caller, true).SetBalance(big.NewInt(balance))
+	return &Context{
+		State:    st,
+		Caller:   caller,
+		Number:   1,
+		GasLimit: 100000,
+	}
+}
+
+func deploy(t *testing.T, vm YVM, ctx *Context, code []byte, value int64) common.Address {
+	contract, _, err := vm.Deploy(ctx, code, big.NewInt(value))
+	if err != nil {
+		t.Fatalf("Deploy() %v", err)
+	}
+	ctx.Nonce++
+	return contract
+}
+
+func TestContractAddress(t *testing.T) {
+	caller := common.BytesToAddress([]byte{1})
+	a0, err := ContractAddress(1, caller, 0)
+	if err != nil {
+		t.Fatalf("ContractAddress() %v", err)
+	}
+	a1, _ := ContractAddress(1, caller, 1)
+	again, _ := ContractAddress(1, caller, 0)
+	if a0 == a1 || a0 != again {
+		t.Errorf("contract address not derived from caller / nonce: %x %x %x", a0, a1, again)
+	}
+	if other, _ := ContractAddress(2, caller, 0); other == a0 {
+		t.Errorf("contract address not derived from chain id: %x", other)
+	}
+	// rendered as contract of the network accounts are on
+	rendered := address.NewContractAddressFromCommonAddress(a0)
+	if parsed, err := address.AddressParse(rendered.String()); err != nil || *parsed.CommonAddress() != a0 {
+		t.Errorf("contract address not parsed back: %v %v", parsed, err)
+	}
+}
+
+func TestDeployAndCall(t *testing.T) {
+	vm := NewYVM()
+	ctx := newTestContext(t, 1000)
+	contract := deploy(t, vm, ctx, counterCode, 100)
+	if !bytes.Equal(ctx.State.GetAccount(contract, false).Code(), counterCode) {
+		t.Fatalf("deployed code mismatch")
+	}
+	if _, _, err := vm.Deploy(&Context{State: ctx.State, Caller: ctx.Caller, GasLimit: ctx.GasLimit}, counterCode, new(big.Int)); err != ErrContractExists {
+		t.Errorf("deploy to existing contract got %v", err)
+	}
+
+	input := common.BytesToHash([]byte{5})
+	for i, expected := range []int64{5, 10} {
+		output, gas, err := vm.Call(ctx, contract, input[:], big.NewInt(1))
+		if err != nil {
+			t.Fatalf("Call() %v", err)
+		}
+		if got := new(big.Int).SetBytes(output).Int64(); got != expected {
+			t.Errorf("call %d output %d, need %d", i, got, expected)
+		}
+		if gas <= GasCall || gas >= ctx.GasLimit {
+			t.Errorf("call %d gas used %d", i, gas)
+		}
+	}
+	if got := ctx.State.GetAccount(contract, false).GetStorage(common.Hash{}); got != common.BytesToHash([]byte{10}) {
+		t.Errorf("storage got %x", got)
+	}
+	if got := ctx.State.GetAccount(contract, false).Balance().Int64(); got != 102 {
+		t.Errorf("contract balance got %d", got)
+	}
+	if got := ctx.State.GetAccount(ctx.Caller, false).Balance().Int64(); got != 898 {
+		t.Errorf("caller balance got %d", got)
+	}
+}
+
+func TestCallFailureReverts(t *testing.T) {
+	vm := NewYVM()
+	ctx := newTestContext(t, 1000)
+	revert := deploy(t, vm, ctx, []byte{
+		byte(PUSH), 1, 1, byte(PUSH), 1, 0, byte(SSTORE),
+		byte(REVERT),
+	}, 0)
+	loop := deploy(t, vm, ctx, []byte{
+		byte(JUMPDEST), byte(PUSH), 1, 0, byte(JUMP),
+	}, 0)
+	badJump := deploy(t, vm, ctx, []byte{
+		byte(PUSH), 1, 2, byte(JUMP),
+	}, 0)
+	underflow := deploy(t, vm, ctx, []byte{byte(ADD)}, 0)
+
+	for _, test := range []struct {
+		contract common.Address
+		err      error
+		allGas   bool
+	}{
+		{revert, ErrExecutionReverted, false},
+		{loop, ErrOutOfGas, true},
+		{badJump, ErrInvalidJump, true},
+		{underflow, ErrStackUnderflow, true},
+		{common.BytesToAddress([]byte{0xee}), ErrNoContractCode, false},
+	} {
+		_, gas, err := vm.Call(ctx, test.contract, nil, big.NewInt(10))
+		if err != test.err {
+			t.Errorf("call %x need %v, got %v", test.contract, test.err, err)
+		}
+		if (gas == ctx.GasLimit) != test.allGas {
+			t.Errorf("call %x gas used %d", test.contract, gas)
+		}
+	}
+	// value and storage changes discarded
+	if got := ctx.State.GetAccount(ctx.Caller, false).Balance().Int64(); got != 1000 {
+		t.Errorf("caller balance got %d", got)
+	}
+	if got := ctx.State.GetAccount(revert, false).GetStorage(common.Hash{}); got != (common.Hash{}) {
+		t.Errorf("reverted storage got %x", got)
+	}
+}
+
+func TestContractTransfer(t *testing.T) {
+	vm := NewYVM()
+	ctx := newTestContext(t, 1000)
+	// send 30 from contract balance to caller
+	contract := deploy(t, vm, ctx, []byte{
+		byte(PUSH), 1, 30, byte(CALLER), byte(TRANSFER),
+	}, 50)
+	if _, _, err := vm.Call(ctx, contract, nil, new(big.Int)); err != nil {
+		t.Fatalf("Call() %v", err)
+	}
+	if got := ctx.State.GetAccount(contract, false).Balance().Int64(); got != 20 {
+		t.Errorf("contract balance got %d", got)
+	}
+	if _, _, err := vm.Call(ctx, contract, nil, new(big.Int)); err != ErrInsufficientBalance {
+		t.Errorf("transfer over balance got %v", err)
+	}
+}
@@ -39,8 +39,6 @@ package node
 
 import (
 	"errors"
-	"net"
-	"net/rpc"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -103,6 +101,9 @@ func NewNodeWithGenesis(conf *config.Config, genesis *core.Genesis) (*Node, erro
 	if err != nil {
 		log.Crit("node: p2p: ", err)
 	}
+	node.p2p.RegChainProvider(node.core.Chain())
+
+	node.rpc = grpc.NewServer(node)
 
 	node.stop = make(chan struct{})
 	return node, nil
@@ -136,7 +137,7 @@ func (n *Node) Start() (err error) {
 		return err
 	}
 
-	if err = n.startIPC(); err != nil {
+	if err = n.rpc.Start(); err != nil {
 		return err
 	}
 
@@ -148,6 +149,7 @@ func (n *Node) Stop() error {
 	defer n.lock.Unlock()
 	log.Info("Node Stop...")
 
+	n.rpc.Stop()
 	n.p2p.Stop()
 	if err := n.core.Stop(); err != nil {
 		return err
@@ -201,41 +203,6 @@ func (n *Node) unlockDataDir() error {
 	return nil
 }
 
-func (n *Node) startIPC() error {
-	if n.config.IPCEndpoint() == "" {
-		return nil
-	}
-
-	handler := rpc.NewServer()
-	jsService := new(JSService)
-	handler.Register(jsService)
-
-	endpoint := n.config.IPCEndpoint()
-	if err := os.MkdirAll(filepath.Dir(endpoint), 0751); err != nil {
-		return err
-	}
-	os.Remove(endpoint) //TODO:?为什么？确定目录在，但把文件删掉
-	listener, err := net.Listen("unix", endpoint)
-	if err != nil {
-		return err
-	}
-
-	os.Chmod(endpoint, 0600)
-
-	go func() {
-		for {
-			conn, err := listener.Accept()
-			if err != nil {
-				log.Error("IPC loop: %v", err)
-				continue
-			}
-			go handler.ServeConn(conn)
-		}
-	}()
-
-	return nil
-}
-
 //get the node id of self
 func (n *Node) NodeID() string {
 	return "aaaa"
@@ -260,15 +227,3 @@ func (n *Node) Core() *core.Core {
 func (n *Node) P2pService() p2p.Service {
 	return n.p2p
 }
-
-//TODO:for test, remove later
-type JSService int
-type Args struct {
-	S string
-}
-
-func (js *JSService) Hello(args *Args, reply *string) error {
-	log.Info(args.S)
-	*reply = args.S + "how are you"
-	return nil
-}
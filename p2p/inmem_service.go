@@ -148,8 +148,7 @@ func (is *InmemService) RegChainProvider(cp ChainProvider) {
 }
 
 func (is *InmemService) GetChainInfo(kind string, key []byte) ([]byte, error) {
-	// TODO:
-	return nil, nil
+	return is.hub.GetChainData(is, kind, key), nil
 }
 
 //Inmem Hub for all InmemService
@@ -194,6 +193,21 @@ func (ih *InmemHub) RemoveNode(node *InmemService) {
 	delete(ih.nodes, node)
 }
 
+//向hub中其他节点请求链数据，返回第一个非空结果
+func (ih *InmemHub) GetChainData(from *InmemService, kind string, key []byte) []byte {
+	ih.lock.RLock()
+	defer ih.lock.RUnlock()
+	for n := range ih.nodes {
+		if n == from || n.cp == nil {
+			continue
+		}
+		if data := n.cp.GetChainData(kind, key); len(data) > 0 {
+			return data
+		}
+	}
+	return nil
+}
+
 func (ih *InmemHub) Broadcast(from *InmemService, message Message) error {
 	ih.lock.RLock()
 	defer ih.lock.RUnlock()
@@ -0,0 +1,277 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package persistent
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/yeeco/gyee/common"
+	bolt "go.etcd.io/bbolt"
+)
+
+// all keys kept in a single bucket
+var boltBucket = []byte("gyee")
+
+// initial size of memory map, file remapped when growing over it,
+//   waiting for read transactions to finish
+const boltInitialMmapSize = 1 << 30
+
+// BoltStorage keeps data in a bolt B+tree file
+//   iterators and snapshots hold a read transaction till released,
+//   which may delay writers growing the file over memory map size
+type BoltStorage struct {
+	db *bolt.DB
+}
+
+func NewBoltStorage(path string) (*BoltStorage, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{
+		Timeout:         time.Second,
+		InitialMmapSize: boltInitialMmapSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &BoltStorage{
+		db: db,
+	}, nil
+}
+
+// value of key in bucket, only valid in transaction
+func boltGet(tx *bolt.Tx, key []byte) ([]byte, bool) {
+	k, v := tx.Bucket(boltBucket).Cursor().Seek(key)
+	if k == nil || !bytes.Equal(k, key) {
+		return nil, false
+	}
+	return v, true
+}
+
+func (storage *BoltStorage) Has(key []byte) (bool, error) {
+	found := false
+	err := storage.db.View(func(tx *bolt.Tx) error {
+		_, found = boltGet(tx, key)
+		return nil
+	})
+	return found, err
+}
+
+func (storage *BoltStorage) Get(key []byte) ([]byte, error) {
+	var val []byte
+	err := storage.db.View(func(tx *bolt.Tx) error {
+		v, found := boltGet(tx, key)
+		if !found {
+			return ErrKeyNotFound
+		}
+		val = common.CopyBytes(v)
+		if val == nil {
+			val = []byte{}
+		}
+		return nil
+	})
+	return val, err
+}
+
+func (storage *BoltStorage) Put(key []byte, value []byte) error {
+	return storage.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put(key, value)
+	})
+}
+
+func (storage *BoltStorage) Del(key []byte) error {
+	return storage.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete(key)
+	})
+}
+
+func (storage *BoltStorage) Close() error {
+	return storage.db.Close()
+}
+
+func (storage *BoltStorage) NewBatch() Batch {
+	return &boltBatch{db: storage.db}
+}
+
+func (storage *BoltStorage) Iterator(prefix []byte, start []byte) Iterator {
+	tx, err := storage.db.Begin(false)
+	if err != nil {
+		return &boltIterator{err: err}
+	}
+	return newBoltIterator(tx, true, prefix, start)
+}
+
+// snapshot as a read transaction
+func (storage *BoltStorage) NewSnapshot() (Snapshot, error) {
+	tx, err := storage.db.Begin(false)
+	if err != nil {
+		return nil, err
+	}
+	return &boltSnapshot{tx: tx}, nil
+}
+
+type boltSnapshot struct {
+	tx *bolt.Tx
+}
+
+func (s *boltSnapshot) Has(key []byte) (bool, error) {
+	_, found := boltGet(s.tx, key)
+	return found, nil
+}
+
+func (s *boltSnapshot) Get(key []byte) ([]byte, error) {
+	v, found := boltGet(s.tx, key)
+	if !found {
+		return nil, ErrKeyNotFound
+	}
+	if v == nil {
+		v = []byte{}
+	}
+	return v, nil
+}
+
+func (s *boltSnapshot) Iterator(prefix []byte, start []byte) Iterator {
+	return newBoltIterator(s.tx, false, prefix, start)
+}
+
+func (s *boltSnapshot) Release() {
+	s.tx.Rollback()
+}
+
+type boltIterator struct {
+	tx     *bolt.Tx
+	owned  bool // tx rolled back on release
+	cursor *bolt.Cursor
+	prefix []byte
+	seek   []byte
+
+	key, value []byte
+	started    bool
+	done       bool
+	err        error
+}
+
+func newBoltIterator(tx *bolt.Tx, owned bool, prefix []byte, start []byte) *boltIterator {
+	return &boltIterator{
+		tx:     tx,
+		owned:  owned,
+		cursor: tx.Bucket(boltBucket).Cursor(),
+		prefix: common.CopyBytes(prefix),
+		seek:   append(common.CopyBytes(prefix), start...),
+	}
+}
+
+func (it *boltIterator) Next() bool {
+	if it.cursor == nil || it.done {
+		return false
+	}
+	var k, v []byte
+	if !it.started {
+		k, v = it.cursor.Seek(it.seek)
+		it.started = true
+	} else {
+		k, v = it.cursor.Next()
+	}
+	if k == nil || !bytes.HasPrefix(k, it.prefix) {
+		it.key, it.value, it.done = nil, nil, true
+		return false
+	}
+	it.key, it.value = k, v
+	return true
+}
+
+func (it *boltIterator) Key() []byte {
+	return it.key
+}
+
+func (it *boltIterator) Value() []byte {
+	return it.value
+}
+
+func (it *boltIterator) Error() error {
+	return it.err
+}
+
+func (it *boltIterator) Release() {
+	if it.owned && it.tx != nil {
+		it.tx.Rollback()
+	}
+	it.tx, it.cursor = nil, nil
+	it.key, it.value = nil, nil
+}
+
+// batch written in a single update transaction
+type boltBatch struct {
+	db      *bolt.DB
+	entries []*kv
+	size    int
+}
+
+func (b *boltBatch) Put(key, value []byte) error {
+	b.entries = append(b.entries, &kv{
+		common.CopyBytes(key), common.CopyBytes(value),
+		false})
+	b.size += len(value)
+	return nil
+}
+
+func (b *boltBatch) Del(key []byte) error {
+	b.entries = append(b.entries, &kv{
+		common.CopyBytes(key), nil,
+		true})
+	b.size += 1
+	return nil
+}
+
+func (b *boltBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *boltBatch) Write() error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return replayEntries(b.entries, &boltWriter{tx.Bucket(boltBucket)})
+	})
+}
+
+func (b *boltBatch) Replay(w Writer) error {
+	return replayEntries(b.entries, w)
+}
+
+func (b *boltBatch) Reset() {
+	b.entries = b.entries[:0]
+	b.size = 0
+}
+
+// adapts bucket of update transaction to Writer
+type boltWriter struct {
+	bucket *bolt.Bucket
+}
+
+func (w *boltWriter) Put(key []byte, value []byte) error {
+	return w.bucket.Put(key, value)
+}
+
+func (w *boltWriter) Del(key []byte) error {
+	return w.bucket.Delete(key)
+}
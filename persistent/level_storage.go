@@ -24,6 +24,8 @@ import (
 	"github.com/syndtr/goleveldb/leveldb"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/opt"
+	"github.com/syndtr/goleveldb/leveldb/util"
+	"github.com/yeeco/gyee/common"
 )
 
 type LevelStorage struct {
@@ -75,10 +77,57 @@ func (storage *LevelStorage) GetLevelDB() *leveldb.DB {
 	return storage.db
 }
 
+// Compact compacts whole key range, dropping deleted and overwritten data
+func (storage *LevelStorage) Compact() error {
+	return storage.db.CompactRange(util.Range{})
+}
+
 func (storage *LevelStorage) NewBatch() Batch {
 	return &ldbBatch{db: storage.db, b: new(leveldb.Batch)}
 }
 
+func (storage *LevelStorage) Iterator(prefix []byte, start []byte) Iterator {
+	return storage.db.NewIterator(prefixRange(prefix, start), nil)
+}
+
+func (storage *LevelStorage) NewSnapshot() (Snapshot, error) {
+	snap, err := storage.db.GetSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &ldbSnapshot{snap: snap}, nil
+}
+
+func prefixRange(prefix []byte, start []byte) *util.Range {
+	r := util.BytesPrefix(prefix)
+	r.Start = append(common.CopyBytes(prefix), start...)
+	return r
+}
+
+type ldbSnapshot struct {
+	snap *leveldb.Snapshot
+}
+
+func (s *ldbSnapshot) Has(key []byte) (bool, error) {
+	return s.snap.Has(key, nil)
+}
+
+func (s *ldbSnapshot) Get(key []byte) ([]byte, error) {
+	val, err := s.snap.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		err = ErrKeyNotFound
+	}
+	return val, err
+}
+
+func (s *ldbSnapshot) Iterator(prefix []byte, start []byte) Iterator {
+	return s.snap.NewIterator(prefixRange(prefix, start), nil)
+}
+
+func (s *ldbSnapshot) Release() {
+	s.snap.Release()
+}
+
 type ldbBatch struct {
 	db   *leveldb.DB
 	b    *leveldb.Batch
@@ -109,3 +158,29 @@ func (b *ldbBatch) Reset() {
 	b.b.Reset()
 	b.size = 0
 }
+
+func (b *ldbBatch) Replay(w Writer) error {
+	r := &ldbReplayer{w: w}
+	if err := b.b.Replay(r); err != nil {
+		return err
+	}
+	return r.err
+}
+
+// adapts Writer to leveldb.BatchReplay, keeping first error
+type ldbReplayer struct {
+	w   Writer
+	err error
+}
+
+func (r *ldbReplayer) Put(key, value []byte) {
+	if r.err == nil {
+		r.err = r.w.Put(key, value)
+	}
+}
+
+func (r *ldbReplayer) Delete(key []byte) {
+	if r.err == nil {
+		r.err = r.w.Del(key)
+	}
+}
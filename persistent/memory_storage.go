@@ -21,13 +21,23 @@
 package persistent
 
 import (
-	"encoding/hex"
-	"github.com/yeeco/gyee/common"
+	"bytes"
+	"sort"
 	"sync"
+
+	"github.com/yeeco/gyee/common"
 )
 
+// MemoryStorage keeps data in a map, shared with snapshots taken
+//   map copied on write while referenced by unreleased snapshot
 type MemoryStorage struct {
-	data *sync.Map
+	lock sync.RWMutex
+	data *memoryData
+}
+
+type memoryData struct {
+	kv   map[string][]byte
+	refs int // unreleased snapshots
 }
 
 type kv struct {
@@ -43,32 +53,56 @@ type memoryBatch struct {
 
 func NewMemoryStorage() *MemoryStorage {
 	return &MemoryStorage{
-		data: new(sync.Map),
+		data: &memoryData{kv: make(map[string][]byte)},
 	}
 }
 
 func (db *MemoryStorage) Has(key []byte) (bool, error) {
-	_, ok := db.data.Load(hex.EncodeToString(key))
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	_, ok := db.data.kv[string(key)]
 	return ok, nil
 }
 
 func (db *MemoryStorage) Get(key []byte) ([]byte, error) {
-	if entry, ok := db.data.Load(hex.EncodeToString(key)); ok {
-		return entry.([]byte), nil
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if entry, ok := db.data.kv[string(key)]; ok {
+		return entry, nil
 	}
 	return nil, ErrKeyNotFound
 }
 
 func (db *MemoryStorage) Put(key []byte, value []byte) error {
-	db.data.Store(hex.EncodeToString(key), common.CopyBytes(value))
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.writable()[string(key)] = common.CopyBytes(value)
 	return nil
 }
 
 func (db *MemoryStorage) Del(key []byte) error {
-	db.data.Delete(hex.EncodeToString(key))
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	delete(db.writable(), string(key))
 	return nil
 }
 
+// map to write, copied if referenced by snapshot, lock held
+func (db *MemoryStorage) writable() map[string][]byte {
+	if db.data.refs > 0 {
+		copied := make(map[string][]byte, len(db.data.kv))
+		for k, v := range db.data.kv {
+			copied[k] = v
+		}
+		db.data = &memoryData{kv: copied}
+	}
+	return db.data.kv
+}
+
 func (db *MemoryStorage) Close() error {
 	return nil
 }
@@ -77,6 +111,104 @@ func (db *MemoryStorage) NewBatch() Batch {
 	return &memoryBatch{db: db}
 }
 
+// Iterator over snapshot of keys with prefix, taken on creation
+func (db *MemoryStorage) Iterator(prefix []byte, start []byte) Iterator {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	return newMemoryIterator(db.data.kv, prefix, start)
+}
+
+func (db *MemoryStorage) NewSnapshot() (Snapshot, error) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.data.refs++
+	return &memorySnapshot{db: db, data: db.data}, nil
+}
+
+type memorySnapshot struct {
+	db       *MemoryStorage
+	data     *memoryData
+	released bool
+}
+
+func (snap *memorySnapshot) Has(key []byte) (bool, error) {
+	_, ok := snap.data.kv[string(key)]
+	return ok, nil
+}
+
+func (snap *memorySnapshot) Get(key []byte) ([]byte, error) {
+	if entry, ok := snap.data.kv[string(key)]; ok {
+		return entry, nil
+	}
+	return nil, ErrKeyNotFound
+}
+
+func (snap *memorySnapshot) Iterator(prefix []byte, start []byte) Iterator {
+	return newMemoryIterator(snap.data.kv, prefix, start)
+}
+
+func (snap *memorySnapshot) Release() {
+	snap.db.lock.Lock()
+	defer snap.db.lock.Unlock()
+
+	if !snap.released {
+		snap.data.refs--
+		snap.released = true
+	}
+}
+
+func newMemoryIterator(data map[string][]byte, prefix []byte, start []byte) *memoryIterator {
+	from := string(append(common.CopyBytes(prefix), start...))
+	it := &memoryIterator{index: -1}
+	for k, v := range data {
+		if !bytes.HasPrefix([]byte(k), prefix) || k < from {
+			continue
+		}
+		it.entries = append(it.entries, &kv{k: []byte(k), v: v})
+	}
+	sort.Slice(it.entries, func(i, j int) bool {
+		return bytes.Compare(it.entries[i].k, it.entries[j].k) < 0
+	})
+	return it
+}
+
+type memoryIterator struct {
+	entries []*kv
+	index   int
+}
+
+func (it *memoryIterator) Next() bool {
+	if it.index >= len(it.entries) {
+		return false
+	}
+	it.index++
+	return it.index < len(it.entries)
+}
+
+func (it *memoryIterator) Key() []byte {
+	if it.index < 0 || it.index >= len(it.entries) {
+		return nil
+	}
+	return it.entries[it.index].k
+}
+
+func (it *memoryIterator) Value() []byte {
+	if it.index < 0 || it.index >= len(it.entries) {
+		return nil
+	}
+	return it.entries[it.index].v
+}
+
+func (it *memoryIterator) Error() error {
+	return nil
+}
+
+func (it *memoryIterator) Release() {
+	it.entries = nil
+}
+
 func (b *memoryBatch) Put(key, value []byte) error {
 	b.entries = append(b.entries, &kv{
 		common.CopyBytes(key), common.CopyBytes(value),
@@ -97,12 +229,36 @@ func (b *memoryBatch) ValueSize() int {
 	return b.size
 }
 
+// write entries at once, not seen partially by readers
 func (b *memoryBatch) Write() error {
+	b.db.lock.Lock()
+	defer b.db.lock.Unlock()
+
+	data := b.db.writable()
 	for _, kv := range b.entries {
 		if kv.del {
-			b.db.Del(kv.k)
+			delete(data, string(kv.k))
+		} else {
+			data[string(kv.k)] = kv.v
+		}
+	}
+	return nil
+}
+
+func (b *memoryBatch) Replay(w Writer) error {
+	return replayEntries(b.entries, w)
+}
+
+func replayEntries(entries []*kv, w Writer) error {
+	for _, kv := range entries {
+		var err error
+		if kv.del {
+			err = w.Del(kv.k)
 		} else {
-			b.db.Put(kv.k, kv.v)
+			err = w.Put(kv.k, kv.v)
+		}
+		if err != nil {
+			return err
 		}
 	}
 	return nil
@@ -43,6 +43,11 @@ type Deleter interface {
 	Del(key []byte) error
 }
 
+type Writer interface {
+	Putter
+	Deleter
+}
+
 type Storage interface {
 	Getter
 	Putter
@@ -51,6 +56,21 @@ type Storage interface {
 	Close() error
 
 	NewBatch() Batch
+
+	// Iterator walks keys with prefix in ascending order, from prefix+start
+	Iterator(prefix []byte, start []byte) Iterator
+
+	// NewSnapshot takes a consistent read-only view of current data
+	NewSnapshot() (Snapshot, error)
+}
+
+// Snapshot of storage, not affected by later writes
+//   must be released after use
+type Snapshot interface {
+	Getter
+
+	Iterator(prefix []byte, start []byte) Iterator
+	Release()
 }
 
 type Batch interface {
@@ -60,4 +80,18 @@ type Batch interface {
 	ValueSize() int
 	Write() error
 	Reset()
+
+	// Replay applies batched writes to w, in order
+	Replay(w Writer) error
+}
+
+// Iterator over key / value pairs of storage in ascending key order
+//   key / value returned are only valid till next call to Next
+//   must be released after use
+type Iterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
 }
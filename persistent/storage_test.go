@@ -0,0 +1,379 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package persistent
+
+import (
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// keys iterated, joined by ","
+func collectKeys(t *testing.T, it Iterator) string {
+	defer it.Release()
+	keys := make([]string, 0)
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	if err := it.Error(); err != nil {
+		t.Errorf("iterator error %v", err)
+	}
+	return strings.Join(keys, ",")
+}
+
+func testStorageIterator(t *testing.T, storage Storage) {
+	for _, key := range []string{"b2", "a1", "b1", "c", "b3", "a"} {
+		storage.Put([]byte(key), []byte("v"+key))
+	}
+	for _, test := range []struct {
+		prefix, start string
+		keys          string
+	}{
+		{"", "", "a,a1,b1,b2,b3,c"},
+		{"b", "", "b1,b2,b3"},
+		{"b", "2", "b2,b3"},
+		{"a", "0", "a1"},
+		{"", "b", "b1,b2,b3,c"},
+		{"d", "", ""},
+	} {
+		keys := collectKeys(t, storage.Iterator([]byte(test.prefix), []byte(test.start)))
+		if keys != test.keys {
+			t.Errorf("prefix %q start %q got %q, need %q", test.prefix, test.start, keys, test.keys)
+		}
+	}
+	// spare capacity of caller prefix not written
+	prefix := make([]byte, 1, 4)
+	prefix[0] = 'b'
+	spare := append(prefix, 'x')
+	if keys := collectKeys(t, storage.Iterator(prefix, []byte("2"))); keys != "b2,b3" || spare[1] != 'x' {
+		t.Errorf("prefix with spare capacity got %q, spare %q", keys, spare)
+	}
+	it := storage.Iterator([]byte("a1"), nil)
+	if !it.Next() || string(it.Value()) != "va1" {
+		t.Errorf("value got %q", it.Value())
+	}
+	it.Release()
+
+	// table keys stripped of prefix
+	table := NewTable(storage, "b")
+	if keys := collectKeys(t, table.Iterator(nil, []byte("2"))); keys != "2,3" {
+		t.Errorf("table got %q", keys)
+	}
+	if keys := collectKeys(t, table.Iterator([]byte("1"), nil)); keys != "1" {
+		t.Errorf("table prefix got %q", keys)
+	}
+}
+
+func testBatchReplay(t *testing.T, storage Storage) {
+	batch := NewTable(storage, "t").NewBatch()
+	batch.Put([]byte("k1"), []byte("v1"))
+	batch.Del([]byte("del"))
+	batch.Put([]byte("k2"), []byte("v2"))
+
+	target := NewMemoryStorage()
+	target.Put([]byte("del"), []byte("old"))
+	if err := batch.Replay(target); err != nil {
+		t.Fatalf("Replay() %v", err)
+	}
+	if ok, _ := target.Has([]byte("del")); ok {
+		t.Errorf("deleted key replayed")
+	}
+	for _, key := range []string{"k1", "k2"} {
+		if v, err := target.Get([]byte(key)); err != nil || string(v) != "v"+key[1:] {
+			t.Errorf("replayed %s got %s %v", key, v, err)
+		}
+	}
+	// batch not written to its own storage by replay
+	if ok, _ := storage.Has([]byte("tk1")); ok {
+		t.Errorf("replay wrote batch storage")
+	}
+}
+
+func testStorageSnapshot(t *testing.T, storage Storage) {
+	storage.Put([]byte("ta"), []byte("1"))
+	storage.Put([]byte("tb"), []byte("1"))
+	snap, err := NewTable(storage, "t").NewSnapshot()
+	if err != nil {
+		t.Fatalf("NewSnapshot() %v", err)
+	}
+	defer snap.Release()
+
+	batch := storage.NewBatch()
+	batch.Put([]byte("ta"), []byte("2"))
+	batch.Del([]byte("tb"))
+	batch.Put([]byte("tc"), []byte("2"))
+	if err := batch.Write(); err != nil {
+		t.Fatalf("batch.Write() %v", err)
+	}
+
+	if v, err := snap.Get([]byte("a")); err != nil || string(v) != "1" {
+		t.Errorf("snapshot a got %s %v", v, err)
+	}
+	if ok, _ := snap.Has([]byte("b")); !ok {
+		t.Errorf("snapshot b deleted")
+	}
+	if _, err := snap.Get([]byte("c")); err != ErrKeyNotFound {
+		t.Errorf("snapshot c got %v", err)
+	}
+	if keys := collectKeys(t, snap.Iterator(nil, nil)); keys != "a,b" {
+		t.Errorf("snapshot keys got %q", keys)
+	}
+	if v, _ := storage.Get([]byte("ta")); string(v) != "2" {
+		t.Errorf("storage a got %s", v)
+	}
+	if keys := collectKeys(t, storage.Iterator([]byte("t"), nil)); keys != "ta,tc" {
+		t.Errorf("storage keys got %q", keys)
+	}
+}
+
+func testStorageBasic(t *testing.T, storage Storage) {
+	key := []byte("key")
+	if _, err := storage.Get(key); err != ErrKeyNotFound {
+		t.Errorf("Get() missing expect %v, got %v", ErrKeyNotFound, err)
+	}
+	if ok, err := storage.Has(key); ok || err != nil {
+		t.Errorf("Has() missing got %v %v", ok, err)
+	}
+	for _, value := range []string{"v1", "v2", ""} {
+		if err := storage.Put(key, []byte(value)); err != nil {
+			t.Fatalf("Put() %v", err)
+		}
+		if v, err := storage.Get(key); err != nil || string(v) != value {
+			t.Errorf("Get() need %q, got %q %v", value, v, err)
+		}
+		if ok, err := storage.Has(key); !ok || err != nil {
+			t.Errorf("Has() got %v %v", ok, err)
+		}
+	}
+	if err := storage.Del(key); err != nil {
+		t.Fatalf("Del() %v", err)
+	}
+	if _, err := storage.Get(key); err != ErrKeyNotFound {
+		t.Errorf("Get() deleted expect %v, got %v", ErrKeyNotFound, err)
+	}
+	// value kept by storage, not by caller
+	value := []byte("value")
+	storage.Put(key, value)
+	value[0] = 'x'
+	if v, _ := storage.Get(key); string(v) != "value" {
+		t.Errorf("stored value changed by caller, got %q", v)
+	}
+}
+
+func testStorageBatch(t *testing.T, storage Storage) {
+	storage.Put([]byte("del"), []byte("v"))
+	batch := storage.NewBatch()
+	batch.Put([]byte("k1"), []byte("v1"))
+	batch.Put([]byte("k2"), []byte("v2"))
+	batch.Del([]byte("del"))
+	if batch.ValueSize() == 0 {
+		t.Errorf("batch ValueSize() 0")
+	}
+	if ok, _ := storage.Has([]byte("k1")); ok {
+		t.Errorf("batch written before Write()")
+	}
+	if err := batch.Write(); err != nil {
+		t.Fatalf("batch.Write() %v", err)
+	}
+	if v, err := storage.Get([]byte("k2")); err != nil || string(v) != "v2" {
+		t.Errorf("batch k2 got %q %v", v, err)
+	}
+	if ok, _ := storage.Has([]byte("del")); ok {
+		t.Errorf("batch deleted key kept")
+	}
+	batch.Reset()
+	if batch.ValueSize() != 0 {
+		t.Errorf("batch ValueSize() after Reset() %d", batch.ValueSize())
+	}
+	batch.Put([]byte("k3"), []byte("v3"))
+	batch.Write()
+	if ok, _ := storage.Has([]byte("k3")); !ok {
+		t.Errorf("batch after Reset() not written")
+	}
+}
+
+// conformance suite, run against each Storage implementation
+//   newStorage returns an empty storage
+func testStorage(t *testing.T, newStorage func(t *testing.T) Storage) {
+	for _, test := range []struct {
+		name string
+		fn   func(t *testing.T, storage Storage)
+	}{
+		{"Basic", testStorageBasic},
+		{"Batch", testStorageBatch},
+		{"Iterator", testStorageIterator},
+		{"BatchReplay", testBatchReplay},
+		{"Snapshot", testStorageSnapshot},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			test.fn(t, newStorage(t))
+		})
+	}
+}
+
+// storage in temp dir, closed and removed on test cleanup
+func newTempStorage(t *testing.T, open func(dir string) (Storage, error)) Storage {
+	dir, err := ioutil.TempDir("", "gyee-storage")
+	if err != nil {
+		t.Fatal(err)
+	}
+	storage, err := open(dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		storage.Close()
+		os.RemoveAll(dir)
+	})
+	return storage
+}
+
+func openLevelStorage(dir string) (Storage, error) {
+	return NewLevelStorage(dir)
+}
+
+func openBoltStorage(dir string) (Storage, error) {
+	return NewBoltStorage(filepath.Join(dir, "bolt.db"))
+}
+
+func TestMemoryStorage(t *testing.T) {
+	testStorage(t, func(t *testing.T) Storage {
+		return NewMemoryStorage()
+	})
+}
+
+func TestLevelStorage(t *testing.T) {
+	testStorage(t, func(t *testing.T) Storage {
+		return newTempStorage(t, openLevelStorage)
+	})
+}
+
+func TestBoltStorage(t *testing.T) {
+	testStorage(t, func(t *testing.T) Storage {
+		return newTempStorage(t, openBoltStorage)
+	})
+}
+
+func TestTableStorage(t *testing.T) {
+	t.Run("Memory", func(t *testing.T) {
+		testStorage(t, func(t *testing.T) Storage {
+			return NewTable(NewMemoryStorage(), "tbl-")
+		})
+	})
+	t.Run("Level", func(t *testing.T) {
+		testStorage(t, func(t *testing.T) Storage {
+			return NewTable(newTempStorage(t, openLevelStorage), "tbl-")
+		})
+	})
+}
+
+func TestMemoryStorageSnapshot(t *testing.T) {
+	storage := NewMemoryStorage()
+	testStorageSnapshot(t, storage)
+
+	// released snapshot no longer copied on write
+	snap, _ := storage.NewSnapshot()
+	snap.Release()
+	snap.Release()
+	data := storage.data
+	storage.Put([]byte("x"), nil)
+	if storage.data != data {
+		t.Errorf("data copied after snapshot released")
+	}
+}
+
+func dirSize(dir string) int64 {
+	var size int64
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// bytes written by process, from /proc/self/io, -1 if not available
+func procWriteBytes() int64 {
+	enc, err := ioutil.ReadFile("/proc/self/io")
+	if err != nil {
+		return -1
+	}
+	for _, line := range strings.Split(string(enc), "\n") {
+		if strings.HasPrefix(line, "wchar:") {
+			n, err := strconv.ParseInt(strings.TrimSpace(line[len("wchar:"):]), 10, 64)
+			if err != nil {
+				return -1
+			}
+			return n
+		}
+	}
+	return -1
+}
+
+// writes batches of random keys, like trie nodes of new blocks, reporting
+//   write-amp:     bytes written to files over bytes put, linux only
+//   space-amp:     size of storage dir over bytes put
+//   disk-bytes/op: size of storage dir per batch
+func benchmarkStorageWrite(b *testing.B, open func(dir string) (Storage, error)) {
+	dir, err := ioutil.TempDir("", "gyee-bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	storage, err := open(dir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	const batchKeys = 100
+	written := 0
+	startWrites := procWriteBytes()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch := storage.NewBatch()
+		for j := 0; j < batchKeys; j++ {
+			key, value := randBytes(32), randBytes(100+rand.Intn(400))
+			batch.Put(key, value)
+			written += len(key) + len(value)
+		}
+		if err := batch.Write(); err != nil {
+			b.Fatal(err)
+		}
+	}
+	b.StopTimer()
+	storage.Close()
+	if startWrites >= 0 {
+		b.ReportMetric(float64(procWriteBytes()-startWrites)/float64(written), "write-amp")
+	}
+	size := dirSize(dir)
+	b.ReportMetric(float64(size)/float64(b.N), "disk-bytes/op")
+	b.ReportMetric(float64(size)/float64(written), "space-amp")
+}
+
+func BenchmarkLevelStorageWrite(b *testing.B) {
+	benchmarkStorageWrite(b, openLevelStorage)
+}
+
+func BenchmarkBoltStorageWrite(b *testing.B) {
+	benchmarkStorageWrite(b, openBoltStorage)
+}
@@ -50,6 +50,75 @@ func (t *table) Close() error {
 	return nil
 }
 
+// Iterator over keys of table, with table prefix stripped
+func (t *table) Iterator(prefix []byte, start []byte) Iterator {
+	return &tableIterator{
+		it:     t.storage.Iterator(append([]byte(t.prefix), prefix...), start),
+		prefix: len(t.prefix),
+	}
+}
+
+func (t *table) NewSnapshot() (Snapshot, error) {
+	snap, err := t.storage.NewSnapshot()
+	if err != nil {
+		return nil, err
+	}
+	return &tableSnapshot{snap: snap, prefix: t.prefix}, nil
+}
+
+type tableSnapshot struct {
+	snap   Snapshot
+	prefix string
+}
+
+func (ts *tableSnapshot) Get(key []byte) ([]byte, error) {
+	return ts.snap.Get(append([]byte(ts.prefix), key...))
+}
+
+func (ts *tableSnapshot) Has(key []byte) (bool, error) {
+	return ts.snap.Has(append([]byte(ts.prefix), key...))
+}
+
+func (ts *tableSnapshot) Iterator(prefix []byte, start []byte) Iterator {
+	return &tableIterator{
+		it:     ts.snap.Iterator(append([]byte(ts.prefix), prefix...), start),
+		prefix: len(ts.prefix),
+	}
+}
+
+func (ts *tableSnapshot) Release() {
+	ts.snap.Release()
+}
+
+type tableIterator struct {
+	it     Iterator
+	prefix int
+}
+
+func (ti *tableIterator) Next() bool {
+	return ti.it.Next()
+}
+
+func (ti *tableIterator) Key() []byte {
+	key := ti.it.Key()
+	if key == nil {
+		return nil
+	}
+	return key[ti.prefix:]
+}
+
+func (ti *tableIterator) Value() []byte {
+	return ti.it.Value()
+}
+
+func (ti *tableIterator) Error() error {
+	return ti.it.Error()
+}
+
+func (ti *tableIterator) Release() {
+	ti.it.Release()
+}
+
 func (t *table) NewBatch() Batch {
 	return &tableBatch{
 		batch:  t.storage.NewBatch(),
@@ -81,3 +150,21 @@ func (tb *tableBatch) Write() error {
 func (tb *tableBatch) Reset() {
 	tb.batch.Reset()
 }
+
+// replay writes of table batch with table prefix stripped
+func (tb *tableBatch) Replay(w Writer) error {
+	return tb.batch.Replay(&tableReplayer{w: w, prefix: len(tb.prefix)})
+}
+
+type tableReplayer struct {
+	w      Writer
+	prefix int
+}
+
+func (r *tableReplayer) Put(key []byte, value []byte) error {
+	return r.w.Put(key[r.prefix:], value)
+}
+
+func (r *tableReplayer) Del(key []byte) error {
+	return r.w.Del(key[r.prefix:])
+}
@@ -24,13 +24,23 @@ import (
 	"context"
 
 	"github.com/yeeco/gyee/rpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type AdminService struct {
 	server RPCServer
+	node   Node
 }
 
 func (s *AdminService) NewAccount(ctx context.Context, req *rpcpb.NewAccountRequest) (*rpcpb.NewAccountResponse, error) {
-
-	return &rpcpb.NewAccountResponse{Address: "new account"}, nil
+	// keystore refuses empty passphrase
+	if len(req.Passphrase) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "empty passphrase")
+	}
+	addr, err := s.node.AccountManager().CreateNewAccount([]byte(req.Passphrase))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "create account: %v", err)
+	}
+	return &rpcpb.NewAccountResponse{Address: addr.String()}, nil
 }
@@ -22,15 +22,229 @@ package rpc
 
 import (
 	"context"
+	"encoding/hex"
+	"strings"
 
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/common/address"
+	"github.com/yeeco/gyee/core"
 	"github.com/yeeco/gyee/rpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type APIService struct {
 	server RPCServer
+	node   Node
 }
 
 func (s *APIService) NodeInfo(ctx context.Context, req *rpcpb.NonParamsRequest) (*rpcpb.NodeInfoResponse, error) {
 
-	return &rpcpb.NodeInfoResponse{Id: "123", Version: 1}, nil
+	return &rpcpb.NodeInfoResponse{Id: s.node.NodeID(), Version: 1}, nil
+}
+
+func (s *APIService) chain() *core.BlockChain {
+	return s.node.Core().Chain()
+}
+
+func (s *APIService) CurrentHeight(ctx context.Context, req *rpcpb.NonParamsRequest) (*rpcpb.CurrentHeightResponse, error) {
+	return &rpcpb.CurrentHeightResponse{Height: s.chain().CurrentBlockHeight()}, nil
+}
+
+func (s *APIService) GetBlockByNumber(ctx context.Context, req *rpcpb.GetBlockByNumberRequest) (*rpcpb.BlockResponse, error) {
+	b := s.chain().GetBlockByNumber(req.Number)
+	if b == nil {
+		return nil, status.Errorf(codes.NotFound, "block %d not found", req.Number)
+	}
+	return blockResponse(b), nil
+}
+
+func (s *APIService) GetBlockByHash(ctx context.Context, req *rpcpb.GetBlockByHashRequest) (*rpcpb.BlockResponse, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	b := s.chain().GetBlockByHash(hash)
+	if b == nil {
+		return nil, status.Errorf(codes.NotFound, "block %v not found", hash)
+	}
+	return blockResponse(b), nil
+}
+
+func (s *APIService) GetTransaction(ctx context.Context, req *rpcpb.GetTransactionRequest) (*rpcpb.TransactionResponse, error) {
+	hash, err := parseHash(req.Hash)
+	if err != nil {
+		return nil, err
+	}
+	tx, lookup := s.chain().GetTransaction(hash)
+	if tx == nil {
+		return nil, status.Errorf(codes.NotFound, "transaction %v not found", hash)
+	}
+	return &rpcpb.TransactionResponse{
+		Transaction: transactionMessage(tx),
+		BlockHash:   lookup.BlockHash.Hex(),
+		BlockNumber: lookup.Number,
+		Index:       lookup.Index,
+		Receipt:     receiptMessage(s.chain().GetReceipt(hash)),
+	}, nil
+}
+
+func (s *APIService) GetAccount(ctx context.Context, req *rpcpb.GetAccountRequest) (*rpcpb.AccountResponse, error) {
+	addr, err := address.AddressParse(req.Address)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "address %q: %v", req.Address, err)
+	}
+	chain := s.chain()
+	b := chain.LastBlock()
+	if at, ok := req.Block.(*rpcpb.GetAccountRequest_Height); ok {
+		if b = chain.GetBlockByNumber(at.Height); b == nil {
+			return nil, status.Errorf(codes.NotFound, "block %d not found", at.Height)
+		}
+	}
+	st, err := chain.StateAt(b.StateRoot())
+	if err != nil {
+		// state of old blocks pruned
+		return nil, status.Errorf(codes.FailedPrecondition, "state at block %d unavailable: %v", b.Number(), err)
+	}
+	resp := &rpcpb.AccountResponse{
+		Address: addr.String(),
+		Balance: "0",
+		Height:  b.Number(),
+	}
+	if account := st.GetAccount(*addr.CommonAddress(), false); account != nil {
+		resp.Nonce = account.Nonce()
+		resp.Balance = account.Balance().String()
+	}
+	return resp, nil
+}
+
+func (s *APIService) GetValidators(ctx context.Context, req *rpcpb.NonParamsRequest) (*rpcpb.ValidatorsResponse, error) {
+	return &rpcpb.ValidatorsResponse{Validators: s.chain().GetValidators()}, nil
+}
+
+// reasons of txs rejected by pool
+var txRejectReasons = map[error]rpcpb.TxRejectReason{
+	core.ErrTxChainID:             rpcpb.TxRejectReason_TX_CHAIN_ID_MISMATCH,
+	core.ErrTxInvalidSig:          rpcpb.TxRejectReason_TX_INVALID_SIGNATURE,
+	core.ErrTxKnown:               rpcpb.TxRejectReason_TX_KNOWN,
+	core.ErrTxNoAccount:           rpcpb.TxRejectReason_TX_NO_ACCOUNT,
+	core.ErrTxNonceTooLow:         rpcpb.TxRejectReason_TX_NONCE_TOO_LOW,
+	core.ErrTxNonceTooFar:         rpcpb.TxRejectReason_TX_NONCE_TOO_FAR,
+	core.ErrTxFeeTooLow:           rpcpb.TxRejectReason_TX_FEE_TOO_LOW,
+	core.ErrTxInsufficientBalance: rpcpb.TxRejectReason_TX_INSUFFICIENT_BALANCE,
+	core.ErrTxReplaceUnderpriced:  rpcpb.TxRejectReason_TX_REPLACE_UNDERPRICED,
+	core.ErrTxAccountFull:         rpcpb.TxRejectReason_TX_ACCOUNT_FULL,
+	core.ErrTxUnderpriced:         rpcpb.TxRejectReason_TX_POOL_UNDERPRICED,
+	core.ErrTxGasFeeTooLow:        rpcpb.TxRejectReason_TX_GAS_FEE_TOO_LOW,
+	core.ErrTxGasLimitTooHigh:     rpcpb.TxRejectReason_TX_GAS_LIMIT_TOO_HIGH,
+}
+
+func (s *APIService) SendRawTransaction(ctx context.Context, req *rpcpb.SendRawTransactionRequest) (*rpcpb.SendRawTransactionResponse, error) {
+	tx := new(core.Transaction)
+	if err := tx.Decode(req.Data); err != nil {
+		return &rpcpb.SendRawTransactionResponse{
+			Reject: rpcpb.TxRejectReason_TX_DECODE_FAILED,
+			Error:  err.Error(),
+		}, nil
+	}
+	resp := &rpcpb.SendRawTransactionResponse{Hash: tx.Hash().Hex()}
+	if err := s.node.Core().TxPool().SubmitTx(tx); err != nil {
+		reason, ok := txRejectReasons[err]
+		if !ok {
+			reason = rpcpb.TxRejectReason_TX_REJECTED
+		}
+		resp.Reject = reason
+		resp.Error = err.Error()
+	}
+	return resp, nil
+}
+
+// hash in hex, with optional 0x prefix
+func parseHash(s string) (common.Hash, error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil || len(b) != common.HashLength {
+		return common.Hash{}, status.Errorf(codes.InvalidArgument, "invalid hash %q", s)
+	}
+	return common.BytesToHash(b), nil
+}
+
+func addressString(addr *common.Address) string {
+	if addr == nil {
+		return ""
+	}
+	return address.NewAddressFromCommonAddress(*addr).String()
+}
+
+func contractAddressString(addr *common.Address) string {
+	if addr == nil {
+		return ""
+	}
+	return address.NewContractAddressFromCommonAddress(*addr).String()
+}
+
+func receiptMessage(r *core.Receipt) *rpcpb.Receipt {
+	if r == nil {
+		return nil
+	}
+	msg := &rpcpb.Receipt{
+		Success:         r.Succeeded(),
+		Fee:             r.Fee().String(),
+		GasUsed:         r.GasUsed(),
+		ContractAddress: contractAddressString(r.ContractAddress()),
+	}
+	if !r.Succeeded() {
+		msg.FailReason = r.FailReason().String()
+	}
+	return msg
+}
+
+func blockResponse(b *core.Block) *rpcpb.BlockResponse {
+	header := b.Header()
+	resp := &rpcpb.BlockResponse{
+		Hash: b.Hash().Hex(),
+		Header: &rpcpb.BlockHeader{
+			ChainID:          header.ChainID,
+			Number:           header.Number,
+			ParentHash:       header.ParentHash.Hex(),
+			ConsensusRoot:    header.ConsensusRoot.Hex(),
+			StateRoot:        header.StateRoot.Hex(),
+			TransactionsRoot: header.TxsRoot.Hex(),
+			ReceiptsRoot:     header.ReceiptsRoot.Hex(),
+			Timestamp:        header.Time,
+			ExtraData:        header.Extra,
+		},
+	}
+	for _, tx := range b.Transactions() {
+		resp.Transactions = append(resp.Transactions, transactionMessage(tx))
+	}
+	return resp
+}
+
+func transactionMessage(tx *core.Transaction) *rpcpb.Transaction {
+	if tx.From() == nil {
+		// txs decoded from block body, recover sender on a copy
+		//   tx may be shared with other subscribers
+		cpy := *tx
+		tx = &cpy
+		tx.VerifySig()
+	}
+	recipient := addressString(tx.Recipient())
+	if tx.Type() == core.TxTypeContractCall {
+		recipient = contractAddressString(tx.Recipient())
+	}
+	return &rpcpb.Transaction{
+		Hash:      tx.Hash().Hex(),
+		ChainID:   tx.ChainID(),
+		Nonce:     tx.Nonce(),
+		From:      addressString(tx.From()),
+		Recipient: recipient,
+		Amount:    tx.Amount().String(),
+		Fee:       tx.Fee().String(),
+		Type:      uint32(tx.Type()),
+		Payload:   tx.Payload(),
+		GasLimit:  tx.GasLimit(),
+	}
 }
@@ -0,0 +1,337 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/yeeco/gyee/accounts"
+	"github.com/yeeco/gyee/common/address"
+	"github.com/yeeco/gyee/config"
+	"github.com/yeeco/gyee/core"
+	"github.com/yeeco/gyee/core/yvm"
+	"github.com/yeeco/gyee/crypto"
+	"github.com/yeeco/gyee/crypto/secp256k1"
+	"github.com/yeeco/gyee/p2p"
+	"github.com/yeeco/gyee/rpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// node with a core over temp chain db, single validator holding all balance
+type testNode struct {
+	config    *config.Config
+	accounts  *accounts.AccountManager
+	core      *core.Core
+	p2p       *testP2p
+	signer    crypto.Signer
+	validator *address.Address
+	dir       string
+}
+
+func newTestNode(t *testing.T) *testNode {
+	dir, err := ioutil.TempDir("", "gyee-rpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := &testNode{dir: dir, p2p: &testP2p{broadcast: make(chan p2p.Message, 16)}}
+	n.signer, n.validator = newTestKey(t)
+	genesis, err := core.NewGenesis(core.TestNetID,
+		map[string]*big.Int{n.validator.String(): big.NewInt(1000000)}, []string{n.validator.String()})
+	if err != nil {
+		t.Fatalf("NewGenesis() %v", err)
+	}
+	n.config = &config.Config{
+		NodeDir: dir,
+		Chain:   &config.ChainConfig{ChainID: uint32(core.TestNetID)},
+		Rpc:     &config.RpcConfig{},
+	}
+	if n.accounts, err = accounts.NewAccountManager(n.config); err != nil {
+		t.Fatalf("NewAccountManager() %v", err)
+	}
+	if n.core, err = core.NewCoreWithGenesis(n, n.config, genesis); err != nil {
+		t.Fatalf("NewCoreWithGenesis() %v", err)
+	}
+	return n
+}
+
+func newTestKey(t *testing.T) (crypto.Signer, *address.Address) {
+	key := secp256k1.NewPrivateKey()
+	pub, err := secp256k1.GetPublicKey(key)
+	if err != nil {
+		t.Fatalf("GetPublicKey() %v", err)
+	}
+	signer := secp256k1.NewSecp256k1Signer()
+	if err := signer.InitSigner(key); err != nil {
+		t.Fatalf("InitSigner() %v", err)
+	}
+	addr, err := address.NewAddressFromPublicKey(pub)
+	if err != nil {
+		t.Fatalf("NewAddressFromPublicKey() %v", err)
+	}
+	return signer, addr
+}
+
+func (n *testNode) NodeID() string                           { return "test" }
+func (n *testNode) Config() *config.Config                   { return n.config }
+func (n *testNode) AccountManager() *accounts.AccountManager { return n.accounts }
+func (n *testNode) P2pService() p2p.Service                  { return n.p2p }
+func (n *testNode) Core() *core.Core                         { return n.core }
+
+// p2p service recording broadcast messages
+type testP2p struct {
+	p2p.Service
+	broadcast chan p2p.Message
+}
+
+func (p *testP2p) BroadcastMessage(msg p2p.Message) error {
+	p.broadcast <- msg
+	return nil
+}
+
+func (n *testNode) close() {
+	n.core.Chain().Stop()
+	os.RemoveAll(n.dir)
+}
+
+// add a block sealing txs to chain
+func (n *testNode) addBlock(t *testing.T, txs ...*core.Transaction) *core.Block {
+	chain := n.core.Chain()
+	parent := chain.LastBlock()
+	b, err := chain.BuildNextBlock(parent, parent.Time()+1, txs)
+	if err != nil {
+		t.Fatalf("BuildNextBlock() %v", err)
+	}
+	if err := b.Sign(n.signer); err != nil {
+		t.Fatalf("block.Sign() %v", err)
+	}
+	if err := chain.AddBlock(b); err != nil {
+		t.Fatalf("AddBlock() %v", err)
+	}
+	return b
+}
+
+func expectCode(t *testing.T, name string, err error, code codes.Code) {
+	if status.Code(err) != code {
+		t.Errorf("%s expect %v, got %v", name, code, err)
+	}
+}
+
+// signed transfer from validator
+func (n *testNode) newTx(t *testing.T, nonce uint64, to *address.Address, amount int64) *core.Transaction {
+	tx := core.NewTransactionWithFee(uint32(core.TestNetID), nonce, to.CommonAddress(), big.NewInt(amount), big.NewInt(1))
+	if err := tx.Sign(n.signer); err != nil {
+		t.Fatalf("tx.Sign() %v", err)
+	}
+	if err := tx.VerifySig(); err != nil {
+		t.Fatalf("tx.VerifySig() %v", err)
+	}
+	return tx
+}
+
+func TestAPIServiceChainQuery(t *testing.T) {
+	n := newTestNode(t)
+	defer n.close()
+	api := &APIService{node: n}
+	ctx := context.Background()
+
+	_, recipient := newTestKey(t)
+	genesis := n.core.Chain().LastBlock()
+	tx := n.newTx(t, 0, recipient, 100)
+	b := n.addBlock(t, tx)
+	n.addBlock(t, n.newTx(t, 1, recipient, 50))
+
+	if resp, err := api.CurrentHeight(ctx, &rpcpb.NonParamsRequest{}); err != nil || resp.Height != 2 {
+		t.Errorf("CurrentHeight() %v %v", resp, err)
+	}
+	byNumber, err := api.GetBlockByNumber(ctx, &rpcpb.GetBlockByNumberRequest{Number: 1})
+	if err != nil {
+		t.Fatalf("GetBlockByNumber() %v", err)
+	}
+	if byNumber.Hash != b.Hash().Hex() || byNumber.Header.Number != 1 ||
+		byNumber.Header.ParentHash != genesis.Hash().Hex() || byNumber.Header.StateRoot != b.StateRoot().Hex() {
+		t.Errorf("GetBlockByNumber() got %v", byNumber)
+	}
+	if len(byNumber.Transactions) != 1 {
+		t.Fatalf("GetBlockByNumber() txs %v", byNumber.Transactions)
+	}
+	if pbTx := byNumber.Transactions[0]; pbTx.Hash != tx.Hash().Hex() || pbTx.From != n.validator.String() ||
+		pbTx.Recipient != recipient.String() || pbTx.Amount != "100" || pbTx.Fee != "1" {
+		t.Errorf("block tx got %v", pbTx)
+	}
+	byHash, err := api.GetBlockByHash(ctx, &rpcpb.GetBlockByHashRequest{Hash: "0x" + genesis.Hash().Hex()})
+	if err != nil || byHash.Header.Number != 0 || len(byHash.Transactions) != 0 {
+		t.Errorf("GetBlockByHash() %v %v", byHash, err)
+	}
+
+	sealed, err := api.GetTransaction(ctx, &rpcpb.GetTransactionRequest{Hash: tx.Hash().Hex()})
+	if err != nil {
+		t.Fatalf("GetTransaction() %v", err)
+	}
+	if sealed.BlockHash != b.Hash().Hex() || sealed.BlockNumber != 1 || sealed.Index != 0 ||
+		sealed.Transaction.From != n.validator.String() {
+		t.Errorf("GetTransaction() got %v", sealed)
+	}
+	if r := sealed.Receipt; r == nil || !r.Success || r.FailReason != "" || r.Fee != "1" {
+		t.Errorf("GetTransaction() receipt %v", r)
+	}
+
+	// fees paid back to validator
+	for _, test := range []struct {
+		block   *rpcpb.GetAccountRequest_Height
+		nonce   uint64
+		balance string
+		at      uint64
+	}{
+		{nil, 2, "999850", 2}, // latest
+		{&rpcpb.GetAccountRequest_Height{Height: 1}, 1, "999900", 1},
+		{&rpcpb.GetAccountRequest_Height{Height: 0}, 0, "1000000", 0}, // genesis
+	} {
+		req := &rpcpb.GetAccountRequest{Address: n.validator.String()}
+		if test.block != nil {
+			req.Block = test.block
+		}
+		account, err := api.GetAccount(ctx, req)
+		if err != nil || account.Nonce != test.nonce || account.Balance != test.balance || account.Height != test.at {
+			t.Errorf("GetAccount() at %v got %v %v", test.block, account, err)
+		}
+	}
+	if account, err := api.GetAccount(ctx, &rpcpb.GetAccountRequest{Address: recipient.String()}); err != nil || account.Balance != "150" {
+		t.Errorf("GetAccount() recipient %v %v", account, err)
+	}
+
+	validators, err := api.GetValidators(ctx, &rpcpb.NonParamsRequest{})
+	if err != nil || len(validators.Validators) != 1 || validators.Validators[0] != n.validator.String() {
+		t.Errorf("GetValidators() %v %v", validators, err)
+	}
+
+	_, err = api.GetBlockByNumber(ctx, &rpcpb.GetBlockByNumberRequest{Number: 3})
+	expectCode(t, "GetBlockByNumber() beyond head", err, codes.NotFound)
+	_, err = api.GetBlockByHash(ctx, &rpcpb.GetBlockByHashRequest{Hash: "beef"})
+	expectCode(t, "GetBlockByHash() short hash", err, codes.InvalidArgument)
+	_, err = api.GetTransaction(ctx, &rpcpb.GetTransactionRequest{Hash: b.Hash().Hex()})
+	expectCode(t, "GetTransaction() unknown", err, codes.NotFound)
+	_, err = api.GetAccount(ctx, &rpcpb.GetAccountRequest{Address: "bad"})
+	expectCode(t, "GetAccount() bad address", err, codes.InvalidArgument)
+	_, err = api.GetAccount(ctx, &rpcpb.GetAccountRequest{Address: recipient.String(),
+		Block: &rpcpb.GetAccountRequest_Height{Height: 5}})
+	expectCode(t, "GetAccount() beyond head", err, codes.NotFound)
+}
+
+func TestAPIServiceContractAddress(t *testing.T) {
+	n := newTestNode(t)
+	defer n.close()
+	api := &APIService{node: n}
+	ctx := context.Background()
+
+	deploy := core.NewDeployTransaction(uint32(core.TestNetID), 0, []byte{0}, new(big.Int), big.NewInt(2000), 2000)
+	if err := deploy.Sign(n.signer); err != nil || deploy.VerifySig() != nil {
+		t.Fatalf("tx.Sign() %v", err)
+	}
+	n.addBlock(t, deploy)
+	resp, err := api.GetTransaction(ctx, &rpcpb.GetTransactionRequest{Hash: deploy.Hash().Hex()})
+	if err != nil || resp.Receipt == nil || !resp.Receipt.Success {
+		t.Fatalf("GetTransaction() deploy %v %v", resp, err)
+	}
+	// contract rendered with contract type, parsed as other addresses
+	contract, err := address.AddressParse(resp.Receipt.ContractAddress)
+	if err != nil || address.AddressType(contract.Raw[address.AddressTypeIndex]) != address.AddressTypeContract {
+		t.Fatalf("contract address %q %v", resp.Receipt.ContractAddress, err)
+	}
+
+	call := core.NewCallTransaction(uint32(core.TestNetID), 1, contract.CommonAddress(), nil, new(big.Int), big.NewInt(1000), 1000)
+	if err := call.Sign(n.signer); err != nil || call.VerifySig() != nil {
+		t.Fatalf("tx.Sign() %v", err)
+	}
+	n.addBlock(t, call)
+	resp, err = api.GetTransaction(ctx, &rpcpb.GetTransactionRequest{Hash: call.Hash().Hex()})
+	if err != nil || resp.Transaction.Recipient != contract.String() {
+		t.Errorf("GetTransaction() call %v %v", resp, err)
+	}
+}
+
+func encodeTx(t *testing.T, tx *core.Transaction) []byte {
+	enc, err := tx.Encode()
+	if err != nil {
+		t.Fatalf("tx.Encode() %v", err)
+	}
+	return enc
+}
+
+func TestAPIServiceSendRawTransaction(t *testing.T) {
+	n := newTestNode(t)
+	defer n.close()
+	api := &APIService{node: n}
+	ctx := context.Background()
+
+	strangerSigner, recipient := newTestKey(t)
+	tx := n.newTx(t, 0, recipient, 100)
+	resp, err := api.SendRawTransaction(ctx, &rpcpb.SendRawTransactionRequest{Data: encodeTx(t, tx)})
+	if err != nil || resp.Reject != rpcpb.TxRejectReason_TX_ACCEPTED || resp.Hash != tx.Hash().Hex() {
+		t.Fatalf("SendRawTransaction() %v %v", resp, err)
+	}
+	if n.core.TxPool().Get(*tx.Hash()) == nil {
+		t.Errorf("accepted tx not in pool")
+	}
+	select {
+	case msg := <-n.p2p.broadcast:
+		if msg.MsgType != p2p.MessageTypeTx || msg.From != n.NodeID() {
+			t.Errorf("broadcast message %v", msg)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("accepted tx not broadcast")
+	}
+
+	wrongChain := core.NewTransactionWithFee(uint32(core.TestNetID)+1, 1, recipient.CommonAddress(), big.NewInt(1), big.NewInt(1))
+	wrongChain.Sign(n.signer)
+	stranger := core.NewTransactionWithFee(uint32(core.TestNetID), 0, recipient.CommonAddress(), big.NewInt(1), big.NewInt(1))
+	stranger.Sign(strangerSigner)
+	lowGasFee := core.NewDeployTransaction(uint32(core.TestNetID), 1, []byte{0}, new(big.Int), big.NewInt(1), 1000)
+	lowGasFee.Sign(n.signer)
+	highGasLimit := core.NewDeployTransaction(uint32(core.TestNetID), 1, []byte{0}, new(big.Int), big.NewInt(1), yvm.MaxGasLimit+1)
+	highGasLimit.Sign(n.signer)
+	for _, test := range []struct {
+		name   string
+		data   []byte
+		reject rpcpb.TxRejectReason
+	}{
+		{"garbage", []byte{0xff, 0xff}, rpcpb.TxRejectReason_TX_DECODE_FAILED},
+		{"resent", encodeTx(t, tx), rpcpb.TxRejectReason_TX_KNOWN},
+		{"wrong chain", encodeTx(t, wrongChain), rpcpb.TxRejectReason_TX_CHAIN_ID_MISMATCH},
+		{"unsigned", encodeTx(t, core.NewTransaction(uint32(core.TestNetID), 1, recipient.CommonAddress(), big.NewInt(1))),
+			rpcpb.TxRejectReason_TX_INVALID_SIGNATURE},
+		{"no account", encodeTx(t, stranger), rpcpb.TxRejectReason_TX_NO_ACCOUNT},
+		{"over balance", encodeTx(t, n.newTx(t, 1, recipient, 2000000)), rpcpb.TxRejectReason_TX_INSUFFICIENT_BALANCE},
+		{"replace", encodeTx(t, n.newTx(t, 0, recipient, 200)), rpcpb.TxRejectReason_TX_REPLACE_UNDERPRICED},
+		{"gas fee", encodeTx(t, lowGasFee), rpcpb.TxRejectReason_TX_GAS_FEE_TOO_LOW},
+		{"gas limit", encodeTx(t, highGasLimit), rpcpb.TxRejectReason_TX_GAS_LIMIT_TOO_HIGH},
+	} {
+		resp, err := api.SendRawTransaction(ctx, &rpcpb.SendRawTransactionRequest{Data: test.data})
+		if err != nil || resp.Reject != test.reject || resp.Error == "" {
+			t.Errorf("SendRawTransaction() %s expect %v, got %v %v", test.name, test.reject, resp, err)
+		}
+	}
+	if pending, queued := n.core.TxPool().Stats(); pending+queued != 1 {
+		t.Errorf("pool got %d pending, %d queued", pending, queued)
+	}
+}
@@ -0,0 +1,321 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/core"
+	"github.com/yeeco/gyee/rpc/pb"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// path prefix of http apis, same as console bridge
+	HTTPAPIVersion = "v1"
+
+	// max size of http request body
+	MaxHTTPBodySize = 1 << 20
+)
+
+// http api calling a grpc service method
+type httpRoute struct {
+	newRequest func() proto.Message
+	call       func(ctx context.Context, req proto.Message) (proto.Message, error)
+}
+
+// apis changing state, POST only
+var httpPostOnly = map[string]bool{
+	"/user/rawtransaction": true,
+	"/admin/account/new":   true,
+}
+
+// httpGateway serves ApiService and AdminService methods as JSON over HTTP
+//   request:  POST /v1/<api> with request message in JSON body, Content-Type application/json
+//             or GET, for apis not changing state
+//   response: {"result": <response message>}
+//             {"error": {"code": <grpc code>, "message": <reason>}}
+// and subscriptions over websocket at /v1/subscribe?topic=<newHeads|pendingTxs|sealedTxs>
+//   each event sent as a result envelope, an error envelope before closing
+// AdminService methods only served if enabled by http_admin
+type httpGateway struct {
+	routes   map[string]*httpRoute
+	api      *APIService
+	quit     chan struct{} // closed to end websocket subscriptions
+	quitOnce sync.Once
+
+	// origins allowed for cross-origin requests, "*" for any
+	cors []string
+
+	marshaler   *jsonpb.Marshaler
+	unmarshaler *jsonpb.Unmarshaler
+}
+
+// admin nil for AdminService not served
+func newHTTPGateway(admin *AdminService, api *APIService, cors []string) *httpGateway {
+	nonParams := func() proto.Message { return new(rpcpb.NonParamsRequest) }
+	routes := map[string]*httpRoute{
+		"/user/nodeinfo": {nonParams, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return api.NodeInfo(ctx, req.(*rpcpb.NonParamsRequest))
+		}},
+		"/user/height": {nonParams, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return api.CurrentHeight(ctx, req.(*rpcpb.NonParamsRequest))
+		}},
+		"/user/getBlockByNumber": {func() proto.Message { return new(rpcpb.GetBlockByNumberRequest) },
+			func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return api.GetBlockByNumber(ctx, req.(*rpcpb.GetBlockByNumberRequest))
+			}},
+		"/user/getBlockByHash": {func() proto.Message { return new(rpcpb.GetBlockByHashRequest) },
+			func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return api.GetBlockByHash(ctx, req.(*rpcpb.GetBlockByHashRequest))
+			}},
+		"/user/getTransaction": {func() proto.Message { return new(rpcpb.GetTransactionRequest) },
+			func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return api.GetTransaction(ctx, req.(*rpcpb.GetTransactionRequest))
+			}},
+		"/user/accountstate": {func() proto.Message { return new(rpcpb.GetAccountRequest) },
+			func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return api.GetAccount(ctx, req.(*rpcpb.GetAccountRequest))
+			}},
+		"/user/validators": {nonParams, func(ctx context.Context, req proto.Message) (proto.Message, error) {
+			return api.GetValidators(ctx, req.(*rpcpb.NonParamsRequest))
+		}},
+		"/user/rawtransaction": {func() proto.Message { return new(rpcpb.SendRawTransactionRequest) },
+			func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return api.SendRawTransaction(ctx, req.(*rpcpb.SendRawTransactionRequest))
+			}},
+	}
+	if admin != nil {
+		routes["/admin/account/new"] = &httpRoute{func() proto.Message { return new(rpcpb.NewAccountRequest) },
+			func(ctx context.Context, req proto.Message) (proto.Message, error) {
+				return admin.NewAccount(ctx, req.(*rpcpb.NewAccountRequest))
+			}}
+	}
+	g := &httpGateway{
+		routes:      make(map[string]*httpRoute, len(routes)),
+		api:         api,
+		quit:        make(chan struct{}),
+		cors:        cors,
+		marshaler:   &jsonpb.Marshaler{EmitDefaults: true},
+		unmarshaler: &jsonpb.Unmarshaler{},
+	}
+	for api, route := range routes {
+		g.routes["/"+HTTPAPIVersion+api] = route
+	}
+	return g
+}
+
+func (g *httpGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/"+HTTPAPIVersion+"/subscribe" {
+		g.serveSubscribe(w, r)
+		return
+	}
+	g.setCORS(w, r)
+	switch r.Method {
+	case http.MethodGet, http.MethodPost:
+	case http.MethodOptions:
+		// cors preflight
+		w.WriteHeader(http.StatusNoContent)
+		return
+	default:
+		g.writeError(w, http.StatusMethodNotAllowed, status.Newf(codes.Unimplemented, "method %s not allowed", r.Method))
+		return
+	}
+
+	route, ok := g.routes[r.URL.Path]
+	if !ok {
+		g.writeError(w, http.StatusNotFound, status.Newf(codes.NotFound, "unknown api %s", r.URL.Path))
+		return
+	}
+	// not reachable by cross-site forms and links
+	if r.Method == http.MethodGet && httpPostOnly[strings.TrimPrefix(r.URL.Path, "/"+HTTPAPIVersion)] {
+		g.writeError(w, http.StatusMethodNotAllowed, status.Newf(codes.Unimplemented, "api %s accepts POST only", r.URL.Path))
+		return
+	}
+	if r.Method == http.MethodPost && !isJSON(r.Header.Get("Content-Type")) {
+		g.writeError(w, http.StatusUnsupportedMediaType, status.New(codes.InvalidArgument, "Content-Type must be application/json"))
+		return
+	}
+	body, err := ioutil.ReadAll(io.LimitReader(r.Body, MaxHTTPBodySize+1))
+	if err != nil {
+		g.writeError(w, http.StatusBadRequest, status.Newf(codes.InvalidArgument, "read request: %v", err))
+		return
+	}
+	if len(body) > MaxHTTPBodySize {
+		g.writeError(w, http.StatusRequestEntityTooLarge, status.New(codes.InvalidArgument, "request too large"))
+		return
+	}
+	req := route.newRequest()
+	if body = bytes.TrimSpace(body); len(body) > 0 {
+		if err := g.unmarshaler.Unmarshal(bytes.NewReader(body), req); err != nil {
+			g.writeError(w, http.StatusBadRequest, status.Newf(codes.InvalidArgument, "decode request: %v", err))
+			return
+		}
+	}
+
+	resp, err := route.call(r.Context(), req)
+	if err != nil {
+		st := status.Convert(err)
+		g.writeError(w, httpStatus(st.Code()), st)
+		return
+	}
+	result, err := g.marshaler.MarshalToString(resp)
+	if err != nil {
+		g.writeError(w, http.StatusInternalServerError, status.Newf(codes.Internal, "encode response: %v", err))
+		return
+	}
+	g.write(w, http.StatusOK, `{"result":`+result+`}`)
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && mediaType == "application/json"
+}
+
+// allow cross-origin requests from configured origins
+func (g *httpGateway) setCORS(w http.ResponseWriter, r *http.Request) {
+	origin := r.Header.Get("Origin")
+	if origin == "" || !g.allowOrigin(origin) {
+		return
+	}
+	h := w.Header()
+	h.Set("Access-Control-Allow-Origin", origin)
+	h.Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+	h.Set("Access-Control-Allow-Headers", "Content-Type")
+	h.Add("Vary", "Origin")
+}
+
+func (g *httpGateway) allowOrigin(origin string) bool {
+	for _, allowed := range g.cors {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *httpGateway) serveSubscribe(w http.ResponseWriter, r *http.Request) {
+	topic := r.URL.Query().Get("topic")
+	t, ok := subscriptionTopics[topic]
+	if !ok {
+		g.writeError(w, http.StatusBadRequest, status.Newf(codes.InvalidArgument, "unknown topic %q", topic))
+		return
+	}
+	websocket.Server{
+		Handshake: g.checkOrigin,
+		Handler: func(ws *websocket.Conn) {
+			g.subscribe(ws, t)
+		},
+	}.ServeHTTP(w, r)
+}
+
+// accept clients without origin, e.g. non-browser ones, same host or allowed origins
+func (g *httpGateway) checkOrigin(config *websocket.Config, r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" || g.allowOrigin(origin) {
+		return nil
+	}
+	if u, err := url.Parse(origin); err == nil && u.Host == r.Host {
+		return nil
+	}
+	return fmt.Errorf("origin %s not allowed", origin)
+}
+
+func (g *httpGateway) subscribe(ws *websocket.Conn, t core.EventType) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		// client messages ignored, till connection closed
+		io.Copy(ioutil.Discard, ws)
+		cancel()
+	}()
+	go func() {
+		select {
+		case <-g.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	err := g.api.subscribe(ctx, t, func(msg proto.Message) error {
+		result, err := g.marshaler.MarshalToString(msg)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(ws, `{"result":`+result+`}`)
+		return err
+	})
+	if st, ok := status.FromError(err); ok && st.Code() == codes.ResourceExhausted {
+		msg, _ := json.Marshal(&httpError{Code: st.Code(), Message: st.Message()})
+		io.WriteString(ws, `{"error":`+string(msg)+`}`)
+	}
+}
+
+// end websocket subscriptions, not closed by http server
+func (g *httpGateway) close() {
+	g.quitOnce.Do(func() { close(g.quit) })
+}
+
+// error envelope of http response
+type httpError struct {
+	Code    codes.Code `json:"code"`
+	Message string     `json:"message"`
+}
+
+func (g *httpGateway) writeError(w http.ResponseWriter, code int, st *status.Status) {
+	msg, _ := json.Marshal(&httpError{Code: st.Code(), Message: st.Message()})
+	g.write(w, code, `{"error":`+string(msg)+`}`)
+}
+
+func (g *httpGateway) write(w http.ResponseWriter, code int, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	io.WriteString(w, body)
+}
+
+// http status of grpc error code
+func httpStatus(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.InvalidArgument, codes.OutOfRange, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,149 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+type testHTTPResponse struct {
+	Result map[string]interface{} `json:"result"`
+	Error  *httpError             `json:"error"`
+}
+
+func doHTTP(t *testing.T, h http.Handler, method, path, body, origin string) (*httptest.ResponseRecorder, *testHTTPResponse) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, req)
+	resp := new(testHTTPResponse)
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), resp); err != nil {
+			t.Fatalf("%s %s response %q: %v", method, path, w.Body.String(), err)
+		}
+	}
+	return w, resp
+}
+
+func TestHTTPGateway(t *testing.T) {
+	n := newTestNode(t)
+	defer n.close()
+	g := newHTTPGateway(&AdminService{node: n}, &APIService{node: n}, []string{"http://console.local"})
+
+	_, recipient := newTestKey(t)
+	b := n.addBlock(t, n.newTx(t, 0, recipient, 100))
+
+	w, resp := doHTTP(t, g, "GET", "/v1/user/height", "", "")
+	if w.Code != http.StatusOK || resp.Error != nil || resp.Result["height"] != "1" {
+		t.Errorf("height got %d %s", w.Code, w.Body)
+	}
+	w, resp = doHTTP(t, g, "POST", "/v1/user/getBlockByNumber", `{"number": 1}`, "")
+	if w.Code != http.StatusOK || resp.Result["hash"] != b.Hash().Hex() {
+		t.Errorf("getBlockByNumber got %d %s", w.Code, w.Body)
+	}
+	if txs, ok := resp.Result["transactions"].([]interface{}); !ok || len(txs) != 1 {
+		t.Errorf("getBlockByNumber txs %v", resp.Result["transactions"])
+	}
+	w, resp = doHTTP(t, g, "POST", "/v1/user/accountstate", `{"address": "`+recipient.String()+`"}`, "")
+	if w.Code != http.StatusOK || resp.Result["balance"] != "100" || resp.Result["nonce"] != "0" {
+		t.Errorf("accountstate got %d %s", w.Code, w.Body)
+	}
+	w, resp = doHTTP(t, g, "POST", "/v1/user/accountstate", `{"address": "`+recipient.String()+`", "height": 0}`, "")
+	if w.Code != http.StatusOK || resp.Result["balance"] != "0" || resp.Result["height"] != "0" {
+		t.Errorf("accountstate at genesis got %d %s", w.Code, w.Body)
+	}
+	w, resp = doHTTP(t, g, "POST", "/v1/admin/account/new", `{"passphrase": "secret"}`, "")
+	if w.Code != http.StatusOK || resp.Result["address"] == "" {
+		t.Errorf("account/new got %d %s", w.Code, w.Body)
+	}
+
+	// error envelopes
+	for _, test := range []struct {
+		method, path, body string
+		status             int
+		code               codes.Code
+	}{
+		{"POST", "/v1/user/getBlockByNumber", `{"number": 5}`, http.StatusNotFound, codes.NotFound},
+		{"POST", "/v1/user/getBlockByHash", `{"hash": "beef"}`, http.StatusBadRequest, codes.InvalidArgument},
+		{"POST", "/v1/user/getBlockByNumber", `{"height": 1}`, http.StatusBadRequest, codes.InvalidArgument},
+		{"POST", "/v1/user/getBlockByNumber", `not json`, http.StatusBadRequest, codes.InvalidArgument},
+		{"POST", "/v1/user/unknown", ``, http.StatusNotFound, codes.NotFound},
+		{"DELETE", "/v1/user/height", ``, http.StatusMethodNotAllowed, codes.Unimplemented},
+		{"GET", "/v1/user/rawtransaction", ``, http.StatusMethodNotAllowed, codes.Unimplemented},
+		{"GET", "/v1/admin/account/new", ``, http.StatusMethodNotAllowed, codes.Unimplemented},
+	} {
+		w, resp := doHTTP(t, g, test.method, test.path, test.body, "")
+		if w.Code != test.status || resp.Error == nil || resp.Error.Code != test.code || resp.Error.Message == "" || resp.Result != nil {
+			t.Errorf("%s %s %s expect %d %v, got %d %s", test.method, test.path, test.body, test.status, test.code, w.Code, w.Body)
+		}
+	}
+
+	// cors
+	w, _ = doHTTP(t, g, "OPTIONS", "/v1/user/height", "", "http://console.local")
+	if w.Code != http.StatusNoContent || w.Header().Get("Access-Control-Allow-Origin") != "http://console.local" {
+		t.Errorf("preflight got %d %v", w.Code, w.Header())
+	}
+	w, _ = doHTTP(t, g, "GET", "/v1/user/height", "", "http://other.local")
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("disallowed origin got %d %v", w.Code, w.Header())
+	}
+}
+
+func TestHTTPGatewayCrossSite(t *testing.T) {
+	n := newTestNode(t)
+	defer n.close()
+	g := newHTTPGateway(nil, &APIService{node: n}, nil)
+
+	// form posts of other sites rejected
+	for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded"} {
+		req := httptest.NewRequest("POST", "/v1/user/height", strings.NewReader(`{}`))
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		w := httptest.NewRecorder()
+		g.ServeHTTP(w, req)
+		if w.Code != http.StatusUnsupportedMediaType {
+			t.Errorf("POST of Content-Type %q got %d %s", contentType, w.Code, w.Body)
+		}
+	}
+	req := httptest.NewRequest("POST", "/v1/user/height", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	w := httptest.NewRecorder()
+	g.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("POST of json with charset got %d %s", w.Code, w.Body)
+	}
+
+	// admin apis not served by default
+	w, resp := doHTTP(t, g, "POST", "/v1/admin/account/new", `{"passphrase": "secret"}`, "")
+	if w.Code != http.StatusNotFound || resp.Error == nil || resp.Error.Code != codes.NotFound {
+		t.Errorf("admin api got %d %s", w.Code, w.Body)
+	}
+	if accounts := n.accounts.Accounts(); len(accounts) != 0 {
+		t.Errorf("account created %v", accounts)
+	}
+}
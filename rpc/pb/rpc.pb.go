@@ -23,6 +23,72 @@ var _ = math.Inf
 // proto package needs to be updated.
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
+// Reason of a tx rejected by node.
+type TxRejectReason int32
+
+const (
+	TxRejectReason_TX_ACCEPTED             TxRejectReason = 0
+	TxRejectReason_TX_DECODE_FAILED        TxRejectReason = 1
+	TxRejectReason_TX_CHAIN_ID_MISMATCH    TxRejectReason = 2
+	TxRejectReason_TX_INVALID_SIGNATURE    TxRejectReason = 3
+	TxRejectReason_TX_KNOWN                TxRejectReason = 4
+	TxRejectReason_TX_NO_ACCOUNT           TxRejectReason = 5
+	TxRejectReason_TX_NONCE_TOO_LOW        TxRejectReason = 6
+	TxRejectReason_TX_NONCE_TOO_FAR        TxRejectReason = 7
+	TxRejectReason_TX_FEE_TOO_LOW          TxRejectReason = 8
+	TxRejectReason_TX_INSUFFICIENT_BALANCE TxRejectReason = 9
+	TxRejectReason_TX_REPLACE_UNDERPRICED  TxRejectReason = 10
+	TxRejectReason_TX_ACCOUNT_FULL         TxRejectReason = 11
+	TxRejectReason_TX_POOL_UNDERPRICED     TxRejectReason = 12
+	TxRejectReason_TX_REJECTED             TxRejectReason = 13
+	TxRejectReason_TX_GAS_FEE_TOO_LOW      TxRejectReason = 14
+	TxRejectReason_TX_GAS_LIMIT_TOO_HIGH   TxRejectReason = 15
+)
+
+var TxRejectReason_name = map[int32]string{
+	0:  "TX_ACCEPTED",
+	1:  "TX_DECODE_FAILED",
+	2:  "TX_CHAIN_ID_MISMATCH",
+	3:  "TX_INVALID_SIGNATURE",
+	4:  "TX_KNOWN",
+	5:  "TX_NO_ACCOUNT",
+	6:  "TX_NONCE_TOO_LOW",
+	7:  "TX_NONCE_TOO_FAR",
+	8:  "TX_FEE_TOO_LOW",
+	9:  "TX_INSUFFICIENT_BALANCE",
+	10: "TX_REPLACE_UNDERPRICED",
+	11: "TX_ACCOUNT_FULL",
+	12: "TX_POOL_UNDERPRICED",
+	13: "TX_REJECTED",
+	14: "TX_GAS_FEE_TOO_LOW",
+	15: "TX_GAS_LIMIT_TOO_HIGH",
+}
+var TxRejectReason_value = map[string]int32{
+	"TX_ACCEPTED":             0,
+	"TX_DECODE_FAILED":        1,
+	"TX_CHAIN_ID_MISMATCH":    2,
+	"TX_INVALID_SIGNATURE":    3,
+	"TX_KNOWN":                4,
+	"TX_NO_ACCOUNT":           5,
+	"TX_NONCE_TOO_LOW":        6,
+	"TX_NONCE_TOO_FAR":        7,
+	"TX_FEE_TOO_LOW":          8,
+	"TX_INSUFFICIENT_BALANCE": 9,
+	"TX_REPLACE_UNDERPRICED":  10,
+	"TX_ACCOUNT_FULL":         11,
+	"TX_POOL_UNDERPRICED":     12,
+	"TX_REJECTED":             13,
+	"TX_GAS_FEE_TOO_LOW":      14,
+	"TX_GAS_LIMIT_TOO_HIGH":   15,
+}
+
+func (x TxRejectReason) String() string {
+	return proto.EnumName(TxRejectReason_name, int32(x))
+}
+func (TxRejectReason) EnumDescriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{0}
+}
+
 // Request message of non params.
 type NonParamsRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -34,7 +100,7 @@ func (m *NonParamsRequest) Reset()         { *m = NonParamsRequest{} }
 func (m *NonParamsRequest) String() string { return proto.CompactTextString(m) }
 func (*NonParamsRequest) ProtoMessage()    {}
 func (*NonParamsRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_b06b22a5979f612a, []int{0}
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{0}
 }
 func (m *NonParamsRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NonParamsRequest.Unmarshal(m, b)
@@ -69,40 +135,984 @@ func (m *NodeInfoResponse) Reset()         { *m = NodeInfoResponse{} }
 func (m *NodeInfoResponse) String() string { return proto.CompactTextString(m) }
 func (*NodeInfoResponse) ProtoMessage()    {}
 func (*NodeInfoResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_b06b22a5979f612a, []int{1}
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{1}
 }
 func (m *NodeInfoResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NodeInfoResponse.Unmarshal(m, b)
 }
-func (m *NodeInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
-	return xxx_messageInfo_NodeInfoResponse.Marshal(b, m, deterministic)
+func (m *NodeInfoResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_NodeInfoResponse.Marshal(b, m, deterministic)
+}
+func (dst *NodeInfoResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_NodeInfoResponse.Merge(dst, src)
+}
+func (m *NodeInfoResponse) XXX_Size() int {
+	return xxx_messageInfo_NodeInfoResponse.Size(m)
+}
+func (m *NodeInfoResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_NodeInfoResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_NodeInfoResponse proto.InternalMessageInfo
+
+func (m *NodeInfoResponse) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *NodeInfoResponse) GetVersion() uint32 {
+	if m != nil {
+		return m.Version
+	}
+	return 0
+}
+
+// Response message of current chain height.
+type CurrentHeightResponse struct {
+	// number of last block in chain.
+	Height               uint64   `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *CurrentHeightResponse) Reset()         { *m = CurrentHeightResponse{} }
+func (m *CurrentHeightResponse) String() string { return proto.CompactTextString(m) }
+func (*CurrentHeightResponse) ProtoMessage()    {}
+func (*CurrentHeightResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{2}
+}
+func (m *CurrentHeightResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_CurrentHeightResponse.Unmarshal(m, b)
+}
+func (m *CurrentHeightResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_CurrentHeightResponse.Marshal(b, m, deterministic)
+}
+func (dst *CurrentHeightResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_CurrentHeightResponse.Merge(dst, src)
+}
+func (m *CurrentHeightResponse) XXX_Size() int {
+	return xxx_messageInfo_CurrentHeightResponse.Size(m)
+}
+func (m *CurrentHeightResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_CurrentHeightResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_CurrentHeightResponse proto.InternalMessageInfo
+
+func (m *CurrentHeightResponse) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type GetBlockByNumberRequest struct {
+	Number               uint64   `protobuf:"varint,1,opt,name=number,proto3" json:"number,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBlockByNumberRequest) Reset()         { *m = GetBlockByNumberRequest{} }
+func (m *GetBlockByNumberRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBlockByNumberRequest) ProtoMessage()    {}
+func (*GetBlockByNumberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{3}
+}
+func (m *GetBlockByNumberRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBlockByNumberRequest.Unmarshal(m, b)
+}
+func (m *GetBlockByNumberRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBlockByNumberRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetBlockByNumberRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBlockByNumberRequest.Merge(dst, src)
+}
+func (m *GetBlockByNumberRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBlockByNumberRequest.Size(m)
+}
+func (m *GetBlockByNumberRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBlockByNumberRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBlockByNumberRequest proto.InternalMessageInfo
+
+func (m *GetBlockByNumberRequest) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+type GetBlockByHashRequest struct {
+	// block hash in hex.
+	Hash                 string   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetBlockByHashRequest) Reset()         { *m = GetBlockByHashRequest{} }
+func (m *GetBlockByHashRequest) String() string { return proto.CompactTextString(m) }
+func (*GetBlockByHashRequest) ProtoMessage()    {}
+func (*GetBlockByHashRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{4}
+}
+func (m *GetBlockByHashRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetBlockByHashRequest.Unmarshal(m, b)
+}
+func (m *GetBlockByHashRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetBlockByHashRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetBlockByHashRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetBlockByHashRequest.Merge(dst, src)
+}
+func (m *GetBlockByHashRequest) XXX_Size() int {
+	return xxx_messageInfo_GetBlockByHashRequest.Size(m)
+}
+func (m *GetBlockByHashRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetBlockByHashRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetBlockByHashRequest proto.InternalMessageInfo
+
+func (m *GetBlockByHashRequest) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+// Block header, mirroring core.BlockHeader, with hashes in hex.
+type BlockHeader struct {
+	ChainID          uint32 `protobuf:"varint,1,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	Number           uint64 `protobuf:"varint,2,opt,name=number,proto3" json:"number,omitempty"`
+	ParentHash       string `protobuf:"bytes,3,opt,name=parentHash,proto3" json:"parentHash,omitempty"`
+	ConsensusRoot    string `protobuf:"bytes,4,opt,name=consensusRoot,proto3" json:"consensusRoot,omitempty"`
+	StateRoot        string `protobuf:"bytes,5,opt,name=stateRoot,proto3" json:"stateRoot,omitempty"`
+	TransactionsRoot string `protobuf:"bytes,6,opt,name=transactionsRoot,proto3" json:"transactionsRoot,omitempty"`
+	ReceiptsRoot     string `protobuf:"bytes,7,opt,name=receiptsRoot,proto3" json:"receiptsRoot,omitempty"`
+	// block time in milli seconds.
+	Timestamp            uint64   `protobuf:"varint,8,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ExtraData            []byte   `protobuf:"bytes,9,opt,name=extraData,proto3" json:"extraData,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BlockHeader) Reset()         { *m = BlockHeader{} }
+func (m *BlockHeader) String() string { return proto.CompactTextString(m) }
+func (*BlockHeader) ProtoMessage()    {}
+func (*BlockHeader) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{5}
+}
+func (m *BlockHeader) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockHeader.Unmarshal(m, b)
+}
+func (m *BlockHeader) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockHeader.Marshal(b, m, deterministic)
+}
+func (dst *BlockHeader) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockHeader.Merge(dst, src)
+}
+func (m *BlockHeader) XXX_Size() int {
+	return xxx_messageInfo_BlockHeader.Size(m)
+}
+func (m *BlockHeader) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockHeader.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockHeader proto.InternalMessageInfo
+
+func (m *BlockHeader) GetChainID() uint32 {
+	if m != nil {
+		return m.ChainID
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetNumber() uint64 {
+	if m != nil {
+		return m.Number
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetParentHash() string {
+	if m != nil {
+		return m.ParentHash
+	}
+	return ""
+}
+
+func (m *BlockHeader) GetConsensusRoot() string {
+	if m != nil {
+		return m.ConsensusRoot
+	}
+	return ""
+}
+
+func (m *BlockHeader) GetStateRoot() string {
+	if m != nil {
+		return m.StateRoot
+	}
+	return ""
+}
+
+func (m *BlockHeader) GetTransactionsRoot() string {
+	if m != nil {
+		return m.TransactionsRoot
+	}
+	return ""
+}
+
+func (m *BlockHeader) GetReceiptsRoot() string {
+	if m != nil {
+		return m.ReceiptsRoot
+	}
+	return ""
+}
+
+func (m *BlockHeader) GetTimestamp() uint64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *BlockHeader) GetExtraData() []byte {
+	if m != nil {
+		return m.ExtraData
+	}
+	return nil
+}
+
+// Transaction, mirroring core.Transaction, with amounts in decimal.
+type Transaction struct {
+	Hash    string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	ChainID uint32 `protobuf:"varint,2,opt,name=chainID,proto3" json:"chainID,omitempty"`
+	Nonce   uint64 `protobuf:"varint,3,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	From    string `protobuf:"bytes,4,opt,name=from,proto3" json:"from,omitempty"`
+	// empty for contract deploying tx.
+	Recipient            string   `protobuf:"bytes,5,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Amount               string   `protobuf:"bytes,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	Fee                  string   `protobuf:"bytes,7,opt,name=fee,proto3" json:"fee,omitempty"`
+	Type                 uint32   `protobuf:"varint,8,opt,name=type,proto3" json:"type,omitempty"`
+	Payload              []byte   `protobuf:"bytes,9,opt,name=payload,proto3" json:"payload,omitempty"`
+	GasLimit             uint64   `protobuf:"varint,10,opt,name=gasLimit,proto3" json:"gasLimit,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Transaction) Reset()         { *m = Transaction{} }
+func (m *Transaction) String() string { return proto.CompactTextString(m) }
+func (*Transaction) ProtoMessage()    {}
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{6}
+}
+func (m *Transaction) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Transaction.Unmarshal(m, b)
+}
+func (m *Transaction) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Transaction.Marshal(b, m, deterministic)
+}
+func (dst *Transaction) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Transaction.Merge(dst, src)
+}
+func (m *Transaction) XXX_Size() int {
+	return xxx_messageInfo_Transaction.Size(m)
+}
+func (m *Transaction) XXX_DiscardUnknown() {
+	xxx_messageInfo_Transaction.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Transaction proto.InternalMessageInfo
+
+func (m *Transaction) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *Transaction) GetChainID() uint32 {
+	if m != nil {
+		return m.ChainID
+	}
+	return 0
+}
+
+func (m *Transaction) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *Transaction) GetFrom() string {
+	if m != nil {
+		return m.From
+	}
+	return ""
+}
+
+func (m *Transaction) GetRecipient() string {
+	if m != nil {
+		return m.Recipient
+	}
+	return ""
+}
+
+func (m *Transaction) GetAmount() string {
+	if m != nil {
+		return m.Amount
+	}
+	return ""
+}
+
+func (m *Transaction) GetFee() string {
+	if m != nil {
+		return m.Fee
+	}
+	return ""
+}
+
+func (m *Transaction) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *Transaction) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *Transaction) GetGasLimit() uint64 {
+	if m != nil {
+		return m.GasLimit
+	}
+	return 0
+}
+
+type BlockResponse struct {
+	Hash                 string         `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	Header               *BlockHeader   `protobuf:"bytes,2,opt,name=header,proto3" json:"header,omitempty"`
+	Transactions         []*Transaction `protobuf:"bytes,3,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}       `json:"-"`
+	XXX_unrecognized     []byte         `json:"-"`
+	XXX_sizecache        int32          `json:"-"`
+}
+
+func (m *BlockResponse) Reset()         { *m = BlockResponse{} }
+func (m *BlockResponse) String() string { return proto.CompactTextString(m) }
+func (*BlockResponse) ProtoMessage()    {}
+func (*BlockResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{7}
+}
+func (m *BlockResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BlockResponse.Unmarshal(m, b)
+}
+func (m *BlockResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BlockResponse.Marshal(b, m, deterministic)
+}
+func (dst *BlockResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BlockResponse.Merge(dst, src)
+}
+func (m *BlockResponse) XXX_Size() int {
+	return xxx_messageInfo_BlockResponse.Size(m)
+}
+func (m *BlockResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_BlockResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BlockResponse proto.InternalMessageInfo
+
+func (m *BlockResponse) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *BlockResponse) GetHeader() *BlockHeader {
+	if m != nil {
+		return m.Header
+	}
+	return nil
+}
+
+func (m *BlockResponse) GetTransactions() []*Transaction {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
+type GetTransactionRequest struct {
+	// tx hash in hex.
+	Hash                 string   `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetTransactionRequest) Reset()         { *m = GetTransactionRequest{} }
+func (m *GetTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*GetTransactionRequest) ProtoMessage()    {}
+func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{8}
+}
+func (m *GetTransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetTransactionRequest.Unmarshal(m, b)
+}
+func (m *GetTransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetTransactionRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetTransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetTransactionRequest.Merge(dst, src)
+}
+func (m *GetTransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_GetTransactionRequest.Size(m)
+}
+func (m *GetTransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetTransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetTransactionRequest proto.InternalMessageInfo
+
+func (m *GetTransactionRequest) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+// Response message of a tx sealed in chain.
+type TransactionResponse struct {
+	Transaction *Transaction `protobuf:"bytes,1,opt,name=transaction,proto3" json:"transaction,omitempty"`
+	// location of the tx in chain.
+	BlockHash   string `protobuf:"bytes,2,opt,name=blockHash,proto3" json:"blockHash,omitempty"`
+	BlockNumber uint64 `protobuf:"varint,3,opt,name=blockNumber,proto3" json:"blockNumber,omitempty"`
+	Index       uint32 `protobuf:"varint,4,opt,name=index,proto3" json:"index,omitempty"`
+	// execution result of the tx.
+	Receipt              *Receipt `protobuf:"bytes,5,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *TransactionResponse) Reset()         { *m = TransactionResponse{} }
+func (m *TransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*TransactionResponse) ProtoMessage()    {}
+func (*TransactionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{9}
+}
+func (m *TransactionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_TransactionResponse.Unmarshal(m, b)
+}
+func (m *TransactionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_TransactionResponse.Marshal(b, m, deterministic)
+}
+func (dst *TransactionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_TransactionResponse.Merge(dst, src)
+}
+func (m *TransactionResponse) XXX_Size() int {
+	return xxx_messageInfo_TransactionResponse.Size(m)
+}
+func (m *TransactionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_TransactionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_TransactionResponse proto.InternalMessageInfo
+
+func (m *TransactionResponse) GetTransaction() *Transaction {
+	if m != nil {
+		return m.Transaction
+	}
+	return nil
+}
+
+func (m *TransactionResponse) GetBlockHash() string {
+	if m != nil {
+		return m.BlockHash
+	}
+	return ""
+}
+
+func (m *TransactionResponse) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+func (m *TransactionResponse) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *TransactionResponse) GetReceipt() *Receipt {
+	if m != nil {
+		return m.Receipt
+	}
+	return nil
+}
+
+// Receipt, mirroring core.Receipt, with fee in decimal.
+type Receipt struct {
+	Success bool `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	// reason of the tx failed, empty if succeeded.
+	FailReason string `protobuf:"bytes,2,opt,name=failReason,proto3" json:"failReason,omitempty"`
+	Fee        string `protobuf:"bytes,3,opt,name=fee,proto3" json:"fee,omitempty"`
+	GasUsed    uint64 `protobuf:"varint,4,opt,name=gasUsed,proto3" json:"gasUsed,omitempty"`
+	// address of contract deployed, empty if none.
+	ContractAddress      string   `protobuf:"bytes,5,opt,name=contractAddress,proto3" json:"contractAddress,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Receipt) Reset()         { *m = Receipt{} }
+func (m *Receipt) String() string { return proto.CompactTextString(m) }
+func (*Receipt) ProtoMessage()    {}
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{10}
+}
+func (m *Receipt) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Receipt.Unmarshal(m, b)
+}
+func (m *Receipt) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Receipt.Marshal(b, m, deterministic)
+}
+func (dst *Receipt) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Receipt.Merge(dst, src)
+}
+func (m *Receipt) XXX_Size() int {
+	return xxx_messageInfo_Receipt.Size(m)
+}
+func (m *Receipt) XXX_DiscardUnknown() {
+	xxx_messageInfo_Receipt.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Receipt proto.InternalMessageInfo
+
+func (m *Receipt) GetSuccess() bool {
+	if m != nil {
+		return m.Success
+	}
+	return false
+}
+
+func (m *Receipt) GetFailReason() string {
+	if m != nil {
+		return m.FailReason
+	}
+	return ""
+}
+
+func (m *Receipt) GetFee() string {
+	if m != nil {
+		return m.Fee
+	}
+	return ""
+}
+
+func (m *Receipt) GetGasUsed() uint64 {
+	if m != nil {
+		return m.GasUsed
+	}
+	return 0
+}
+
+func (m *Receipt) GetContractAddress() string {
+	if m != nil {
+		return m.ContractAddress
+	}
+	return ""
+}
+
+type GetAccountRequest struct {
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	// block of the state, latest block if not set.
+	//
+	// Types that are valid to be assigned to Block:
+	//   *GetAccountRequest_Height
+	Block                isGetAccountRequest_Block `protobuf_oneof:"block"`
+	XXX_NoUnkeyedLiteral struct{}                  `json:"-"`
+	XXX_unrecognized     []byte                    `json:"-"`
+	XXX_sizecache        int32                     `json:"-"`
+}
+
+func (m *GetAccountRequest) Reset()         { *m = GetAccountRequest{} }
+func (m *GetAccountRequest) String() string { return proto.CompactTextString(m) }
+func (*GetAccountRequest) ProtoMessage()    {}
+func (*GetAccountRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{11}
+}
+func (m *GetAccountRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetAccountRequest.Unmarshal(m, b)
+}
+func (m *GetAccountRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetAccountRequest.Marshal(b, m, deterministic)
+}
+func (dst *GetAccountRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetAccountRequest.Merge(dst, src)
+}
+func (m *GetAccountRequest) XXX_Size() int {
+	return xxx_messageInfo_GetAccountRequest.Size(m)
+}
+func (m *GetAccountRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetAccountRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetAccountRequest proto.InternalMessageInfo
+
+func (m *GetAccountRequest) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+type isGetAccountRequest_Block interface {
+	isGetAccountRequest_Block()
+}
+
+type GetAccountRequest_Height struct {
+	Height uint64 `protobuf:"varint,2,opt,name=height,proto3,oneof"`
+}
+
+func (*GetAccountRequest_Height) isGetAccountRequest_Block() {}
+
+func (m *GetAccountRequest) GetBlock() isGetAccountRequest_Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *GetAccountRequest) GetHeight() uint64 {
+	if x, ok := m.GetBlock().(*GetAccountRequest_Height); ok {
+		return x.Height
+	}
+	return 0
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*GetAccountRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _GetAccountRequest_OneofMarshaler, _GetAccountRequest_OneofUnmarshaler, _GetAccountRequest_OneofSizer, []interface{}{
+		(*GetAccountRequest_Height)(nil),
+	}
+}
+
+func _GetAccountRequest_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*GetAccountRequest)
+	// block
+	switch x := m.Block.(type) {
+	case *GetAccountRequest_Height:
+		b.EncodeVarint(2<<3 | proto.WireVarint)
+		b.EncodeVarint(uint64(x.Height))
+	case nil:
+	default:
+		return fmt.Errorf("GetAccountRequest.Block has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _GetAccountRequest_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*GetAccountRequest)
+	switch tag {
+	case 2: // block.height
+		if wire != proto.WireVarint {
+			return true, proto.ErrInternalBadWireType
+		}
+		x, err := b.DecodeVarint()
+		m.Block = &GetAccountRequest_Height{x}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _GetAccountRequest_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*GetAccountRequest)
+	// block
+	switch x := m.Block.(type) {
+	case *GetAccountRequest_Height:
+		n += 1 // tag and wire
+		n += proto.SizeVarint(uint64(x.Height))
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+type AccountResponse struct {
+	Address string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Nonce   uint64 `protobuf:"varint,2,opt,name=nonce,proto3" json:"nonce,omitempty"`
+	// balance in decimal.
+	Balance string `protobuf:"bytes,3,opt,name=balance,proto3" json:"balance,omitempty"`
+	// block height of the state.
+	Height               uint64   `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *AccountResponse) Reset()         { *m = AccountResponse{} }
+func (m *AccountResponse) String() string { return proto.CompactTextString(m) }
+func (*AccountResponse) ProtoMessage()    {}
+func (*AccountResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{12}
+}
+func (m *AccountResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_AccountResponse.Unmarshal(m, b)
+}
+func (m *AccountResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_AccountResponse.Marshal(b, m, deterministic)
+}
+func (dst *AccountResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_AccountResponse.Merge(dst, src)
+}
+func (m *AccountResponse) XXX_Size() int {
+	return xxx_messageInfo_AccountResponse.Size(m)
+}
+func (m *AccountResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_AccountResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_AccountResponse proto.InternalMessageInfo
+
+func (m *AccountResponse) GetAddress() string {
+	if m != nil {
+		return m.Address
+	}
+	return ""
+}
+
+func (m *AccountResponse) GetNonce() uint64 {
+	if m != nil {
+		return m.Nonce
+	}
+	return 0
+}
+
+func (m *AccountResponse) GetBalance() string {
+	if m != nil {
+		return m.Balance
+	}
+	return ""
+}
+
+func (m *AccountResponse) GetHeight() uint64 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type ValidatorsResponse struct {
+	Validators           []string `protobuf:"bytes,1,rep,name=validators,proto3" json:"validators,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ValidatorsResponse) Reset()         { *m = ValidatorsResponse{} }
+func (m *ValidatorsResponse) String() string { return proto.CompactTextString(m) }
+func (*ValidatorsResponse) ProtoMessage()    {}
+func (*ValidatorsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{13}
+}
+func (m *ValidatorsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ValidatorsResponse.Unmarshal(m, b)
+}
+func (m *ValidatorsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ValidatorsResponse.Marshal(b, m, deterministic)
+}
+func (dst *ValidatorsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ValidatorsResponse.Merge(dst, src)
+}
+func (m *ValidatorsResponse) XXX_Size() int {
+	return xxx_messageInfo_ValidatorsResponse.Size(m)
+}
+func (m *ValidatorsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ValidatorsResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ValidatorsResponse proto.InternalMessageInfo
+
+func (m *ValidatorsResponse) GetValidators() []string {
+	if m != nil {
+		return m.Validators
+	}
+	return nil
+}
+
+type SendRawTransactionRequest struct {
+	// tx encoded by core.Transaction.Encode.
+	Data                 []byte   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendRawTransactionRequest) Reset()         { *m = SendRawTransactionRequest{} }
+func (m *SendRawTransactionRequest) String() string { return proto.CompactTextString(m) }
+func (*SendRawTransactionRequest) ProtoMessage()    {}
+func (*SendRawTransactionRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{14}
+}
+func (m *SendRawTransactionRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendRawTransactionRequest.Unmarshal(m, b)
+}
+func (m *SendRawTransactionRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendRawTransactionRequest.Marshal(b, m, deterministic)
+}
+func (dst *SendRawTransactionRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendRawTransactionRequest.Merge(dst, src)
+}
+func (m *SendRawTransactionRequest) XXX_Size() int {
+	return xxx_messageInfo_SendRawTransactionRequest.Size(m)
+}
+func (m *SendRawTransactionRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendRawTransactionRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendRawTransactionRequest proto.InternalMessageInfo
+
+func (m *SendRawTransactionRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type SendRawTransactionResponse struct {
+	// tx hash in hex, empty if tx not decoded.
+	Hash string `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// TX_ACCEPTED if tx added to pool.
+	Reject TxRejectReason `protobuf:"varint,2,opt,name=reject,proto3,enum=rpcpb.TxRejectReason" json:"reject,omitempty"`
+	// description of the reject reason.
+	Error                string   `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SendRawTransactionResponse) Reset()         { *m = SendRawTransactionResponse{} }
+func (m *SendRawTransactionResponse) String() string { return proto.CompactTextString(m) }
+func (*SendRawTransactionResponse) ProtoMessage()    {}
+func (*SendRawTransactionResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{15}
+}
+func (m *SendRawTransactionResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SendRawTransactionResponse.Unmarshal(m, b)
+}
+func (m *SendRawTransactionResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SendRawTransactionResponse.Marshal(b, m, deterministic)
+}
+func (dst *SendRawTransactionResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SendRawTransactionResponse.Merge(dst, src)
+}
+func (m *SendRawTransactionResponse) XXX_Size() int {
+	return xxx_messageInfo_SendRawTransactionResponse.Size(m)
+}
+func (m *SendRawTransactionResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SendRawTransactionResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SendRawTransactionResponse proto.InternalMessageInfo
+
+func (m *SendRawTransactionResponse) GetHash() string {
+	if m != nil {
+		return m.Hash
+	}
+	return ""
+}
+
+func (m *SendRawTransactionResponse) GetReject() TxRejectReason {
+	if m != nil {
+		return m.Reject
+	}
+	return TxRejectReason_TX_ACCEPTED
+}
+
+func (m *SendRawTransactionResponse) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+// Txs output by consensus engine to be sealed at height.
+type SealedTxsResponse struct {
+	Height uint64 `protobuf:"varint,1,opt,name=height,proto3" json:"height,omitempty"`
+	// consensus time in milli seconds.
+	Timestamp uint64 `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// tx hashes in hex.
+	TxHashes             []string `protobuf:"bytes,3,rep,name=txHashes,proto3" json:"txHashes,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SealedTxsResponse) Reset()         { *m = SealedTxsResponse{} }
+func (m *SealedTxsResponse) String() string { return proto.CompactTextString(m) }
+func (*SealedTxsResponse) ProtoMessage()    {}
+func (*SealedTxsResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{16}
+}
+func (m *SealedTxsResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SealedTxsResponse.Unmarshal(m, b)
 }
-func (dst *NodeInfoResponse) XXX_Merge(src proto.Message) {
-	xxx_messageInfo_NodeInfoResponse.Merge(dst, src)
+func (m *SealedTxsResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SealedTxsResponse.Marshal(b, m, deterministic)
 }
-func (m *NodeInfoResponse) XXX_Size() int {
-	return xxx_messageInfo_NodeInfoResponse.Size(m)
+func (dst *SealedTxsResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SealedTxsResponse.Merge(dst, src)
 }
-func (m *NodeInfoResponse) XXX_DiscardUnknown() {
-	xxx_messageInfo_NodeInfoResponse.DiscardUnknown(m)
+func (m *SealedTxsResponse) XXX_Size() int {
+	return xxx_messageInfo_SealedTxsResponse.Size(m)
+}
+func (m *SealedTxsResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_SealedTxsResponse.DiscardUnknown(m)
 }
 
-var xxx_messageInfo_NodeInfoResponse proto.InternalMessageInfo
+var xxx_messageInfo_SealedTxsResponse proto.InternalMessageInfo
 
-func (m *NodeInfoResponse) GetId() string {
+func (m *SealedTxsResponse) GetHeight() uint64 {
 	if m != nil {
-		return m.Id
+		return m.Height
 	}
-	return ""
+	return 0
 }
 
-func (m *NodeInfoResponse) GetVersion() uint32 {
+func (m *SealedTxsResponse) GetTimestamp() uint64 {
 	if m != nil {
-		return m.Version
+		return m.Timestamp
 	}
 	return 0
 }
 
+func (m *SealedTxsResponse) GetTxHashes() []string {
+	if m != nil {
+		return m.TxHashes
+	}
+	return nil
+}
+
 type NewAccountRequest struct {
 	Passphrase           string   `protobuf:"bytes,1,opt,name=passphrase,proto3" json:"passphrase,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
@@ -114,7 +1124,7 @@ func (m *NewAccountRequest) Reset()         { *m = NewAccountRequest{} }
 func (m *NewAccountRequest) String() string { return proto.CompactTextString(m) }
 func (*NewAccountRequest) ProtoMessage()    {}
 func (*NewAccountRequest) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_b06b22a5979f612a, []int{2}
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{17}
 }
 func (m *NewAccountRequest) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NewAccountRequest.Unmarshal(m, b)
@@ -152,7 +1162,7 @@ func (m *NewAccountResponse) Reset()         { *m = NewAccountResponse{} }
 func (m *NewAccountResponse) String() string { return proto.CompactTextString(m) }
 func (*NewAccountResponse) ProtoMessage()    {}
 func (*NewAccountResponse) Descriptor() ([]byte, []int) {
-	return fileDescriptor_rpc_b06b22a5979f612a, []int{3}
+	return fileDescriptor_rpc_f8b08d73c57f1ff0, []int{18}
 }
 func (m *NewAccountResponse) XXX_Unmarshal(b []byte) error {
 	return xxx_messageInfo_NewAccountResponse.Unmarshal(m, b)
@@ -182,8 +1192,24 @@ func (m *NewAccountResponse) GetAddress() string {
 func init() {
 	proto.RegisterType((*NonParamsRequest)(nil), "rpcpb.NonParamsRequest")
 	proto.RegisterType((*NodeInfoResponse)(nil), "rpcpb.NodeInfoResponse")
+	proto.RegisterType((*CurrentHeightResponse)(nil), "rpcpb.CurrentHeightResponse")
+	proto.RegisterType((*GetBlockByNumberRequest)(nil), "rpcpb.GetBlockByNumberRequest")
+	proto.RegisterType((*GetBlockByHashRequest)(nil), "rpcpb.GetBlockByHashRequest")
+	proto.RegisterType((*BlockHeader)(nil), "rpcpb.BlockHeader")
+	proto.RegisterType((*Transaction)(nil), "rpcpb.Transaction")
+	proto.RegisterType((*BlockResponse)(nil), "rpcpb.BlockResponse")
+	proto.RegisterType((*GetTransactionRequest)(nil), "rpcpb.GetTransactionRequest")
+	proto.RegisterType((*TransactionResponse)(nil), "rpcpb.TransactionResponse")
+	proto.RegisterType((*Receipt)(nil), "rpcpb.Receipt")
+	proto.RegisterType((*GetAccountRequest)(nil), "rpcpb.GetAccountRequest")
+	proto.RegisterType((*AccountResponse)(nil), "rpcpb.AccountResponse")
+	proto.RegisterType((*ValidatorsResponse)(nil), "rpcpb.ValidatorsResponse")
+	proto.RegisterType((*SendRawTransactionRequest)(nil), "rpcpb.SendRawTransactionRequest")
+	proto.RegisterType((*SendRawTransactionResponse)(nil), "rpcpb.SendRawTransactionResponse")
+	proto.RegisterType((*SealedTxsResponse)(nil), "rpcpb.SealedTxsResponse")
 	proto.RegisterType((*NewAccountRequest)(nil), "rpcpb.NewAccountRequest")
 	proto.RegisterType((*NewAccountResponse)(nil), "rpcpb.NewAccountResponse")
+	proto.RegisterEnum("rpcpb.TxRejectReason", TxRejectReason_name, TxRejectReason_value)
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -263,6 +1289,19 @@ var _AdminService_serviceDesc = grpc.ServiceDesc{
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type ApiServiceClient interface {
 	NodeInfo(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*NodeInfoResponse, error)
+	// chain queries
+	CurrentHeight(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*CurrentHeightResponse, error)
+	GetBlockByNumber(ctx context.Context, in *GetBlockByNumberRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	GetBlockByHash(ctx context.Context, in *GetBlockByHashRequest, opts ...grpc.CallOption) (*BlockResponse, error)
+	GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*AccountResponse, error)
+	GetValidators(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*ValidatorsResponse, error)
+	// submit signed tx
+	SendRawTransaction(ctx context.Context, in *SendRawTransactionRequest, opts ...grpc.CallOption) (*SendRawTransactionResponse, error)
+	// subscriptions, stream ends with RESOURCE_EXHAUSTED if subscriber falls behind
+	SubscribeNewHeads(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (ApiService_SubscribeNewHeadsClient, error)
+	SubscribePendingTxs(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (ApiService_SubscribePendingTxsClient, error)
+	SubscribeSealedTxs(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (ApiService_SubscribeSealedTxsClient, error)
 }
 
 type apiServiceClient struct {
@@ -282,9 +1321,181 @@ func (c *apiServiceClient) NodeInfo(ctx context.Context, in *NonParamsRequest, o
 	return out, nil
 }
 
+func (c *apiServiceClient) CurrentHeight(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*CurrentHeightResponse, error) {
+	out := new(CurrentHeightResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ApiService/CurrentHeight", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetBlockByNumber(ctx context.Context, in *GetBlockByNumberRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ApiService/GetBlockByNumber", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetBlockByHash(ctx context.Context, in *GetBlockByHashRequest, opts ...grpc.CallOption) (*BlockResponse, error) {
+	out := new(BlockResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ApiService/GetBlockByHash", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetTransaction(ctx context.Context, in *GetTransactionRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ApiService/GetTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetAccount(ctx context.Context, in *GetAccountRequest, opts ...grpc.CallOption) (*AccountResponse, error) {
+	out := new(AccountResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ApiService/GetAccount", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) GetValidators(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (*ValidatorsResponse, error) {
+	out := new(ValidatorsResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ApiService/GetValidators", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) SendRawTransaction(ctx context.Context, in *SendRawTransactionRequest, opts ...grpc.CallOption) (*SendRawTransactionResponse, error) {
+	out := new(SendRawTransactionResponse)
+	err := c.cc.Invoke(ctx, "/rpcpb.ApiService/SendRawTransaction", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *apiServiceClient) SubscribeNewHeads(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (ApiService_SubscribeNewHeadsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ApiService_serviceDesc.Streams[0], "/rpcpb.ApiService/SubscribeNewHeads", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiServiceSubscribeNewHeadsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ApiService_SubscribeNewHeadsClient interface {
+	Recv() (*BlockResponse, error)
+	grpc.ClientStream
+}
+
+type apiServiceSubscribeNewHeadsClient struct {
+	grpc.ClientStream
+}
+
+func (x *apiServiceSubscribeNewHeadsClient) Recv() (*BlockResponse, error) {
+	m := new(BlockResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *apiServiceClient) SubscribePendingTxs(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (ApiService_SubscribePendingTxsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ApiService_serviceDesc.Streams[1], "/rpcpb.ApiService/SubscribePendingTxs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiServiceSubscribePendingTxsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ApiService_SubscribePendingTxsClient interface {
+	Recv() (*Transaction, error)
+	grpc.ClientStream
+}
+
+type apiServiceSubscribePendingTxsClient struct {
+	grpc.ClientStream
+}
+
+func (x *apiServiceSubscribePendingTxsClient) Recv() (*Transaction, error) {
+	m := new(Transaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *apiServiceClient) SubscribeSealedTxs(ctx context.Context, in *NonParamsRequest, opts ...grpc.CallOption) (ApiService_SubscribeSealedTxsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_ApiService_serviceDesc.Streams[2], "/rpcpb.ApiService/SubscribeSealedTxs", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &apiServiceSubscribeSealedTxsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ApiService_SubscribeSealedTxsClient interface {
+	Recv() (*SealedTxsResponse, error)
+	grpc.ClientStream
+}
+
+type apiServiceSubscribeSealedTxsClient struct {
+	grpc.ClientStream
+}
+
+func (x *apiServiceSubscribeSealedTxsClient) Recv() (*SealedTxsResponse, error) {
+	m := new(SealedTxsResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // ApiServiceServer is the server API for ApiService service.
 type ApiServiceServer interface {
 	NodeInfo(context.Context, *NonParamsRequest) (*NodeInfoResponse, error)
+	// chain queries
+	CurrentHeight(context.Context, *NonParamsRequest) (*CurrentHeightResponse, error)
+	GetBlockByNumber(context.Context, *GetBlockByNumberRequest) (*BlockResponse, error)
+	GetBlockByHash(context.Context, *GetBlockByHashRequest) (*BlockResponse, error)
+	GetTransaction(context.Context, *GetTransactionRequest) (*TransactionResponse, error)
+	GetAccount(context.Context, *GetAccountRequest) (*AccountResponse, error)
+	GetValidators(context.Context, *NonParamsRequest) (*ValidatorsResponse, error)
+	// submit signed tx
+	SendRawTransaction(context.Context, *SendRawTransactionRequest) (*SendRawTransactionResponse, error)
+	// subscriptions, stream ends with RESOURCE_EXHAUSTED if subscriber falls behind
+	SubscribeNewHeads(*NonParamsRequest, ApiService_SubscribeNewHeadsServer) error
+	SubscribePendingTxs(*NonParamsRequest, ApiService_SubscribePendingTxsServer) error
+	SubscribeSealedTxs(*NonParamsRequest, ApiService_SubscribeSealedTxsServer) error
 }
 
 func RegisterApiServiceServer(s *grpc.Server, srv ApiServiceServer) {
@@ -309,6 +1520,195 @@ func _ApiService_NodeInfo_Handler(srv interface{}, ctx context.Context, dec func
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ApiService_CurrentHeight_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NonParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServiceServer).CurrentHeight(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ApiService/CurrentHeight",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServiceServer).CurrentHeight(ctx, req.(*NonParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiService_GetBlockByNumber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockByNumberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServiceServer).GetBlockByNumber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ApiService/GetBlockByNumber",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServiceServer).GetBlockByNumber(ctx, req.(*GetBlockByNumberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiService_GetBlockByHash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBlockByHashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServiceServer).GetBlockByHash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ApiService/GetBlockByHash",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServiceServer).GetBlockByHash(ctx, req.(*GetBlockByHashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiService_GetTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServiceServer).GetTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ApiService/GetTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServiceServer).GetTransaction(ctx, req.(*GetTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiService_GetAccount_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAccountRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServiceServer).GetAccount(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ApiService/GetAccount",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServiceServer).GetAccount(ctx, req.(*GetAccountRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiService_GetValidators_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NonParamsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServiceServer).GetValidators(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ApiService/GetValidators",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServiceServer).GetValidators(ctx, req.(*NonParamsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiService_SendRawTransaction_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRawTransactionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ApiServiceServer).SendRawTransaction(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/rpcpb.ApiService/SendRawTransaction",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ApiServiceServer).SendRawTransaction(ctx, req.(*SendRawTransactionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ApiService_SubscribeNewHeads_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NonParamsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApiServiceServer).SubscribeNewHeads(m, &apiServiceSubscribeNewHeadsServer{stream})
+}
+
+type ApiService_SubscribeNewHeadsServer interface {
+	Send(*BlockResponse) error
+	grpc.ServerStream
+}
+
+type apiServiceSubscribeNewHeadsServer struct {
+	grpc.ServerStream
+}
+
+func (x *apiServiceSubscribeNewHeadsServer) Send(m *BlockResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ApiService_SubscribePendingTxs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NonParamsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApiServiceServer).SubscribePendingTxs(m, &apiServiceSubscribePendingTxsServer{stream})
+}
+
+type ApiService_SubscribePendingTxsServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
+}
+
+type apiServiceSubscribePendingTxsServer struct {
+	grpc.ServerStream
+}
+
+func (x *apiServiceSubscribePendingTxsServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ApiService_SubscribeSealedTxs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(NonParamsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ApiServiceServer).SubscribeSealedTxs(m, &apiServiceSubscribeSealedTxsServer{stream})
+}
+
+type ApiService_SubscribeSealedTxsServer interface {
+	Send(*SealedTxsResponse) error
+	grpc.ServerStream
+}
+
+type apiServiceSubscribeSealedTxsServer struct {
+	grpc.ServerStream
+}
+
+func (x *apiServiceSubscribeSealedTxsServer) Send(m *SealedTxsResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 var _ApiService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "rpcpb.ApiService",
 	HandlerType: (*ApiServiceServer)(nil),
@@ -317,28 +1717,141 @@ var _ApiService_serviceDesc = grpc.ServiceDesc{
 			MethodName: "NodeInfo",
 			Handler:    _ApiService_NodeInfo_Handler,
 		},
+		{
+			MethodName: "CurrentHeight",
+			Handler:    _ApiService_CurrentHeight_Handler,
+		},
+		{
+			MethodName: "GetBlockByNumber",
+			Handler:    _ApiService_GetBlockByNumber_Handler,
+		},
+		{
+			MethodName: "GetBlockByHash",
+			Handler:    _ApiService_GetBlockByHash_Handler,
+		},
+		{
+			MethodName: "GetTransaction",
+			Handler:    _ApiService_GetTransaction_Handler,
+		},
+		{
+			MethodName: "GetAccount",
+			Handler:    _ApiService_GetAccount_Handler,
+		},
+		{
+			MethodName: "GetValidators",
+			Handler:    _ApiService_GetValidators_Handler,
+		},
+		{
+			MethodName: "SendRawTransaction",
+			Handler:    _ApiService_SendRawTransaction_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeNewHeads",
+			Handler:       _ApiService_SubscribeNewHeads_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribePendingTxs",
+			Handler:       _ApiService_SubscribePendingTxs_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeSealedTxs",
+			Handler:       _ApiService_SubscribeSealedTxs_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "rpc.proto",
 }
 
-func init() { proto.RegisterFile("rpc.proto", fileDescriptor_rpc_b06b22a5979f612a) }
-
-var fileDescriptor_rpc_b06b22a5979f612a = []byte{
-	// 235 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x6c, 0x90, 0x4d, 0x4b, 0x03, 0x31,
-	0x10, 0x86, 0xbb, 0x0b, 0x5a, 0x3b, 0xa8, 0xe8, 0x5c, 0x8c, 0x3d, 0x48, 0xc9, 0xa9, 0xa7, 0x3d,
-	0xb4, 0x57, 0x11, 0x16, 0x4f, 0x82, 0x14, 0xd9, 0xfe, 0x82, 0x34, 0x19, 0x31, 0x87, 0x26, 0x71,
-	0x66, 0x5b, 0xff, 0xbe, 0x58, 0x13, 0xba, 0x7e, 0x1c, 0x67, 0x86, 0xe7, 0x7d, 0x93, 0x07, 0x26,
-	0x9c, 0x6c, 0x93, 0x38, 0xf6, 0x11, 0x4f, 0x38, 0xd9, 0xb4, 0xd1, 0x08, 0x57, 0xab, 0x18, 0x5e,
-	0x0c, 0x9b, 0xad, 0x74, 0xf4, 0xbe, 0x23, 0xe9, 0xf5, 0xfd, 0xd7, 0xce, 0xd1, 0x53, 0x78, 0x8d,
-	0x1d, 0x49, 0x8a, 0x41, 0x08, 0x2f, 0xa1, 0xf6, 0x4e, 0x55, 0xb3, 0x6a, 0x3e, 0xe9, 0x6a, 0xef,
-	0x50, 0xc1, 0x78, 0x4f, 0x2c, 0x3e, 0x06, 0x55, 0xcf, 0xaa, 0xf9, 0x45, 0x57, 0x46, 0xbd, 0x84,
-	0xeb, 0x15, 0x7d, 0xb4, 0xd6, 0xc6, 0x5d, 0xe8, 0x73, 0x24, 0xde, 0x01, 0x24, 0x23, 0x92, 0xde,
-	0xd8, 0x08, 0xe5, 0x98, 0xc1, 0x46, 0x37, 0x80, 0x43, 0x28, 0x97, 0x2a, 0x18, 0x1b, 0xe7, 0x98,
-	0x44, 0x32, 0x52, 0xc6, 0xc5, 0x1a, 0xce, 0x5b, 0xb7, 0xf5, 0x61, 0x4d, 0xbc, 0xf7, 0x96, 0xf0,
-	0x11, 0xe0, 0xc8, 0xa3, 0x6a, 0x0e, 0x9f, 0x6b, 0xfe, 0xbc, 0x63, 0x7a, 0xfb, 0xcf, 0xe5, 0xbb,
-	0x4c, 0x8f, 0x16, 0xcf, 0x00, 0x6d, 0xf2, 0x25, 0xf2, 0x01, 0xce, 0x8a, 0x05, 0xbc, 0x29, 0xd8,
-	0x2f, 0x55, 0xd3, 0xe3, 0xe1, 0xa7, 0x2f, 0x3d, 0xda, 0x9c, 0x1e, 0x3c, 0x2f, 0x3f, 0x03, 0x00,
-	0x00, 0xff, 0xff, 0x04, 0xe4, 0x1a, 0x88, 0x74, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("rpc.proto", fileDescriptor_rpc_f8b08d73c57f1ff0) }
+
+var fileDescriptor_rpc_f8b08d73c57f1ff0 = []byte{
+	// 1332 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x57, 0xdd, 0x72, 0xda, 0xc8,
+	0x12, 0x36, 0x3f, 0x36, 0xd0, 0x18, 0x2c, 0x8f, 0xff, 0x14, 0x4e, 0x2a, 0xc5, 0x51, 0x9d, 0x0b,
+	0x57, 0x4e, 0x1d, 0xe7, 0xac, 0x93, 0xda, 0xab, 0xad, 0xad, 0x95, 0x41, 0x18, 0x25, 0x44, 0x50,
+	0x83, 0x9c, 0x50, 0xb5, 0x17, 0xaa, 0x41, 0x1a, 0x1b, 0xed, 0x1a, 0x89, 0x95, 0x44, 0xe2, 0x3c,
+	0xc1, 0x3e, 0xc3, 0x3e, 0xc5, 0x3e, 0xc0, 0x3e, 0xc2, 0x3e, 0x50, 0x6e, 0xb7, 0x66, 0x34, 0x12,
+	0x92, 0x01, 0xe7, 0x4e, 0xfd, 0x4d, 0xf7, 0x37, 0xfd, 0x37, 0x4d, 0x03, 0xb5, 0x60, 0x61, 0x5f,
+	0x2c, 0x02, 0x3f, 0xf2, 0xd1, 0x6e, 0xb0, 0xb0, 0x17, 0x53, 0x05, 0x81, 0x64, 0xf8, 0xde, 0x88,
+	0x04, 0x64, 0x1e, 0x62, 0xfa, 0xdb, 0x92, 0x86, 0x91, 0xf2, 0x03, 0xc3, 0x1c, 0xaa, 0x7b, 0xb7,
+	0x3e, 0xa6, 0xe1, 0xc2, 0xf7, 0x42, 0x8a, 0x9a, 0x50, 0x74, 0x1d, 0xb9, 0xd0, 0x2e, 0x9c, 0xd7,
+	0x70, 0xd1, 0x75, 0x90, 0x0c, 0x95, 0x4f, 0x34, 0x08, 0x5d, 0xdf, 0x93, 0x8b, 0xed, 0xc2, 0x79,
+	0x03, 0x27, 0xa2, 0xf2, 0x0a, 0x4e, 0x3a, 0xcb, 0x20, 0xa0, 0x5e, 0xd4, 0xa7, 0xee, 0xdd, 0x2c,
+	0x4a, 0x29, 0x4e, 0x61, 0x6f, 0xc6, 0x11, 0x4e, 0x53, 0xc6, 0x42, 0x52, 0xbe, 0x83, 0xb3, 0x6b,
+	0x1a, 0x5d, 0xdd, 0xfb, 0xf6, 0xaf, 0x57, 0x5f, 0x8c, 0xe5, 0x7c, 0x4a, 0x03, 0xe1, 0x09, 0x33,
+	0xf1, 0x38, 0x90, 0x98, 0xc4, 0x92, 0xf2, 0x5f, 0x38, 0x59, 0x99, 0xf4, 0x49, 0x38, 0x4b, 0x0c,
+	0x10, 0x94, 0x67, 0x24, 0x9c, 0x09, 0x47, 0xf9, 0xb7, 0xf2, 0x67, 0x11, 0xea, 0x5c, 0xb5, 0x4f,
+	0x89, 0x43, 0x03, 0xe6, 0xba, 0x3d, 0x23, 0xae, 0xa7, 0x77, 0xb9, 0x5a, 0x03, 0x27, 0x62, 0xe6,
+	0xba, 0x62, 0xf6, 0x3a, 0xf4, 0x02, 0x60, 0x41, 0x78, 0x44, 0x8c, 0xbb, 0xc4, 0xb9, 0x33, 0x08,
+	0xfa, 0x0f, 0x34, 0x6c, 0x16, 0xa2, 0x17, 0x2e, 0x43, 0xec, 0xfb, 0x91, 0x5c, 0xe6, 0x2a, 0x79,
+	0x10, 0x3d, 0x87, 0x5a, 0x18, 0x91, 0x88, 0x72, 0x8d, 0x5d, 0xae, 0xb1, 0x02, 0xd0, 0x4b, 0x90,
+	0xa2, 0x80, 0x78, 0x21, 0xb1, 0x23, 0xd7, 0xf7, 0x62, 0x9a, 0x3d, 0xae, 0xb4, 0x86, 0x23, 0x05,
+	0xf6, 0x03, 0x6a, 0x53, 0x77, 0x11, 0xc5, 0x7a, 0x15, 0xae, 0x97, 0xc3, 0xd8, 0x6d, 0x91, 0x3b,
+	0xa7, 0x61, 0x44, 0xe6, 0x0b, 0xb9, 0xca, 0xc3, 0x59, 0x01, 0xec, 0x94, 0x3e, 0x44, 0x01, 0xe9,
+	0x92, 0x88, 0xc8, 0xb5, 0x76, 0xe1, 0x7c, 0x1f, 0xaf, 0x00, 0xe5, 0x6b, 0x01, 0xea, 0xe6, 0xea,
+	0xd2, 0x4d, 0x59, 0xcd, 0x66, 0xb1, 0x98, 0xcf, 0xe2, 0x31, 0xec, 0x7a, 0xbe, 0x67, 0x53, 0x9e,
+	0xa8, 0x32, 0x8e, 0x05, 0xc6, 0x71, 0x1b, 0xf8, 0x73, 0x91, 0x1a, 0xfe, 0xcd, 0xbc, 0x08, 0xa8,
+	0xed, 0x2e, 0x5c, 0xea, 0xa5, 0x19, 0x49, 0x01, 0x56, 0x0d, 0x32, 0xf7, 0x97, 0x5e, 0x92, 0x07,
+	0x21, 0x21, 0x09, 0x4a, 0xb7, 0x94, 0x8a, 0xa0, 0xd9, 0x27, 0xe3, 0x8e, 0xbe, 0x2c, 0x28, 0x0f,
+	0xb3, 0x81, 0xf9, 0x37, 0xf3, 0x6f, 0x41, 0xbe, 0xdc, 0xfb, 0xc4, 0x11, 0xf1, 0x25, 0x22, 0x6a,
+	0x41, 0xf5, 0x8e, 0x84, 0x03, 0x77, 0xee, 0x46, 0x32, 0x70, 0x17, 0x53, 0x59, 0xf9, 0xbd, 0x00,
+	0x0d, 0xde, 0x2b, 0x69, 0xd7, 0x6e, 0x8a, 0xfd, 0x25, 0xeb, 0x64, 0xd6, 0x4b, 0x3c, 0xf4, 0xfa,
+	0x25, 0xba, 0xe0, 0x8f, 0xe9, 0x22, 0xd3, 0x65, 0x58, 0x68, 0xa0, 0xef, 0x61, 0x3f, 0x5b, 0x3f,
+	0xb9, 0xd4, 0x2e, 0x65, 0x2c, 0x32, 0x59, 0xc6, 0x39, 0x3d, 0xd1, 0xe2, 0xd9, 0xf3, 0x27, 0x5a,
+	0xfc, 0xef, 0x02, 0x1c, 0xe5, 0x54, 0x85, 0xf3, 0x6f, 0xa0, 0x9e, 0x21, 0xe5, 0x26, 0x9b, 0xef,
+	0xce, 0xaa, 0xb1, 0xb2, 0x4c, 0x79, 0x24, 0xec, 0x9a, 0x62, 0x5c, 0x96, 0x14, 0x40, 0x6d, 0xa8,
+	0x73, 0x21, 0x7e, 0xa9, 0xa2, 0xc8, 0x59, 0x88, 0x35, 0x80, 0xeb, 0x39, 0xf4, 0x81, 0xd7, 0xba,
+	0x81, 0x63, 0x01, 0x9d, 0x43, 0x45, 0x34, 0x28, 0x2f, 0x75, 0xfd, 0xb2, 0x29, 0xfc, 0xc0, 0x31,
+	0x8a, 0x93, 0x63, 0xe5, 0x8f, 0x02, 0x54, 0x04, 0xc8, 0xca, 0x18, 0x2e, 0x6d, 0x9b, 0x86, 0x21,
+	0xf7, 0xbe, 0x8a, 0x13, 0x91, 0x3d, 0xca, 0x5b, 0xe2, 0xde, 0x63, 0x4a, 0x42, 0x31, 0x84, 0x6a,
+	0x38, 0x83, 0x24, 0x6d, 0x52, 0x5a, 0xb5, 0x89, 0x0c, 0x95, 0x3b, 0x12, 0xde, 0x84, 0xd4, 0xe1,
+	0x9e, 0x95, 0x71, 0x22, 0xa2, 0x73, 0x38, 0xb0, 0x7d, 0x2f, 0x0a, 0x88, 0x1d, 0xa9, 0x8e, 0x13,
+	0xb0, 0xdb, 0xe2, 0x76, 0x7c, 0x0c, 0x2b, 0x23, 0x38, 0xbc, 0xa6, 0x91, 0x6a, 0xdb, 0xac, 0x15,
+	0x93, 0x92, 0xc8, 0x50, 0x21, 0xc2, 0x2c, 0xae, 0x4a, 0x22, 0x22, 0x39, 0x9d, 0x79, 0x7c, 0xa2,
+	0xf4, 0x77, 0x92, 0xa9, 0x77, 0x55, 0x81, 0x5d, 0x9e, 0x33, 0x25, 0x84, 0x83, 0x94, 0x4e, 0x94,
+	0x6d, 0x3b, 0x5f, 0xfa, 0xb6, 0x8a, 0xd9, 0xb7, 0x25, 0x43, 0x65, 0x4a, 0xee, 0x49, 0xf2, 0xe6,
+	0x6a, 0x38, 0x11, 0x33, 0x33, 0xb7, 0x9c, 0x9b, 0xb9, 0x6f, 0x00, 0x7d, 0x20, 0xf7, 0xae, 0x43,
+	0x22, 0x3f, 0x08, 0xd3, 0x7b, 0x5f, 0x00, 0x7c, 0x4a, 0x51, 0xb9, 0xd0, 0x2e, 0xb1, 0x94, 0xae,
+	0x10, 0xe5, 0x15, 0x3c, 0x1b, 0x53, 0xcf, 0xc1, 0xe4, 0xf3, 0xe6, 0xbe, 0x74, 0xd8, 0x34, 0x29,
+	0xf0, 0xd7, 0xc6, 0xbf, 0x95, 0x25, 0xb4, 0x36, 0x19, 0x3c, 0xf1, 0xb4, 0xfe, 0x07, 0x7b, 0x01,
+	0xfd, 0x85, 0xda, 0x71, 0xc2, 0x9a, 0x97, 0x27, 0x49, 0xb3, 0x3e, 0x60, 0x0e, 0xc7, 0xc5, 0xc5,
+	0x42, 0x89, 0xe5, 0x83, 0x06, 0x81, 0x1f, 0x88, 0xb8, 0x63, 0x41, 0xa1, 0x70, 0x38, 0xa6, 0xe4,
+	0x9e, 0x3a, 0xe6, 0x43, 0xf8, 0xad, 0x9f, 0x9f, 0xfc, 0xa0, 0x2c, 0x3e, 0x1e, 0x94, 0x2d, 0xa8,
+	0x46, 0x0f, 0xac, 0xef, 0x69, 0xfc, 0x74, 0x6b, 0x38, 0x95, 0x95, 0xd7, 0x70, 0x68, 0xd0, 0xcf,
+	0x8f, 0x7a, 0x81, 0xff, 0x56, 0x84, 0xe1, 0x62, 0x16, 0x90, 0x90, 0x8a, 0xd0, 0x32, 0x88, 0x72,
+	0x01, 0x28, 0x6b, 0xf4, 0xad, 0x8a, 0xbf, 0xfc, 0x5a, 0x84, 0x66, 0x3e, 0x78, 0x74, 0x00, 0x75,
+	0x73, 0x62, 0xa9, 0x9d, 0x8e, 0x36, 0x32, 0xb5, 0xae, 0xb4, 0x83, 0x8e, 0x41, 0x32, 0x27, 0x56,
+	0x57, 0xeb, 0x0c, 0xbb, 0x9a, 0xd5, 0x53, 0xf5, 0x81, 0xd6, 0x95, 0x0a, 0x48, 0x86, 0x63, 0x73,
+	0x62, 0x75, 0xfa, 0xaa, 0x6e, 0x58, 0x7a, 0xd7, 0x7a, 0xaf, 0x8f, 0xdf, 0xab, 0x66, 0xa7, 0x2f,
+	0x15, 0xc5, 0x89, 0x6e, 0x7c, 0x50, 0x07, 0x7a, 0xd7, 0x1a, 0xeb, 0xd7, 0x86, 0x6a, 0xde, 0x60,
+	0x4d, 0x2a, 0xa1, 0x7d, 0xa8, 0x9a, 0x13, 0xeb, 0x9d, 0x31, 0xfc, 0x68, 0x48, 0x65, 0x74, 0x08,
+	0x0d, 0x73, 0x62, 0x19, 0x43, 0x76, 0xd7, 0xf0, 0xc6, 0x30, 0xa5, 0x5d, 0x71, 0x95, 0x31, 0x34,
+	0x3a, 0x9a, 0x65, 0x0e, 0x87, 0xd6, 0x60, 0xf8, 0x51, 0xda, 0x5b, 0x43, 0x7b, 0x2a, 0x96, 0x2a,
+	0x08, 0x41, 0xd3, 0x9c, 0x58, 0x3d, 0x6d, 0xa5, 0x59, 0x45, 0xff, 0x82, 0x33, 0x7e, 0xf5, 0xf8,
+	0xa6, 0xd7, 0xd3, 0x3b, 0xba, 0x66, 0x98, 0xd6, 0x95, 0x3a, 0x50, 0x8d, 0x8e, 0x26, 0xd5, 0x50,
+	0x0b, 0x4e, 0xcd, 0x89, 0x85, 0xb5, 0xd1, 0x40, 0xed, 0x68, 0xd6, 0x8d, 0xd1, 0xd5, 0xf0, 0x08,
+	0xeb, 0x1d, 0xad, 0x2b, 0x01, 0x3a, 0x82, 0x03, 0x73, 0x92, 0x38, 0x62, 0xf5, 0x6e, 0x06, 0x03,
+	0xa9, 0x8e, 0xce, 0xe0, 0xc8, 0x9c, 0x58, 0xa3, 0xe1, 0x70, 0x90, 0xd3, 0xde, 0x17, 0x29, 0xc2,
+	0xda, 0x5b, 0xad, 0xc3, 0x52, 0xd4, 0x40, 0xa7, 0x80, 0xcc, 0x89, 0x75, 0xad, 0x8e, 0x73, 0xfe,
+	0x34, 0xd1, 0x33, 0x38, 0x11, 0xf8, 0x40, 0x7f, 0xaf, 0x9b, 0xfc, 0xa4, 0xaf, 0x5f, 0xf7, 0xa5,
+	0x83, 0xcb, 0x31, 0xec, 0xab, 0xce, 0xdc, 0xf5, 0xc6, 0x34, 0xf8, 0xe4, 0xda, 0x14, 0x75, 0x00,
+	0x56, 0x95, 0x43, 0xb2, 0x68, 0xcc, 0xb5, 0x0e, 0x68, 0x3d, 0xdb, 0x70, 0x12, 0x97, 0x59, 0xd9,
+	0xb9, 0xfc, 0x6b, 0x0f, 0x40, 0x5d, 0xb8, 0x09, 0xe7, 0x8f, 0x50, 0x4d, 0x56, 0x2d, 0x74, 0x96,
+	0xd8, 0x3d, 0xda, 0xc7, 0x5a, 0xab, 0x83, 0xfc, 0x52, 0xa6, 0xec, 0xa0, 0x3e, 0x34, 0x72, 0xcb,
+	0xd6, 0x76, 0x92, 0xe7, 0xe2, 0x60, 0xe3, 0x6e, 0xa6, 0xec, 0xa0, 0xb7, 0x20, 0x3d, 0xde, 0xc2,
+	0xd0, 0x0b, 0x61, 0xb3, 0x65, 0x3d, 0x6b, 0x1d, 0x67, 0x7f, 0xf7, 0x32, 0x5c, 0x3d, 0x68, 0xe6,
+	0xd7, 0x33, 0xf4, 0x7c, 0x8d, 0x29, 0xb3, 0xb5, 0x6d, 0xe5, 0x19, 0x70, 0x9e, 0xec, 0x26, 0x92,
+	0xe1, 0x59, 0x1f, 0x41, 0xad, 0xd6, 0x86, 0x5f, 0xb6, 0x15, 0xdb, 0x4f, 0x00, 0xab, 0xd1, 0x9d,
+	0xd6, 0x6f, 0x6d, 0x9a, 0xb7, 0x4e, 0xc5, 0xc9, 0x5a, 0xf1, 0x90, 0x06, 0x8d, 0x6b, 0x1a, 0xad,
+	0x06, 0xe7, 0xf6, 0x6c, 0x27, 0x3d, 0xb0, 0x3e, 0x64, 0x95, 0x1d, 0xf4, 0x33, 0xa0, 0xf5, 0xa9,
+	0x88, 0xda, 0xc2, 0x64, 0xeb, 0x84, 0x6d, 0xfd, 0xfb, 0x09, 0x8d, 0x4c, 0xee, 0x0f, 0xc7, 0xcb,
+	0x69, 0x68, 0x07, 0xee, 0x94, 0x1a, 0xf4, 0x33, 0xdb, 0x46, 0x9e, 0xf0, 0x73, 0x4b, 0xe6, 0xff,
+	0x5f, 0x40, 0x3d, 0x38, 0x4a, 0x79, 0x46, 0xd4, 0x73, 0x5c, 0xef, 0xce, 0x7c, 0x78, 0x82, 0x69,
+	0xc3, 0x56, 0xc1, 0x79, 0xde, 0x01, 0x4a, 0x79, 0xd2, 0xa1, 0xbc, 0x9d, 0x46, 0x4e, 0x63, 0x7c,
+	0x34, 0xbf, 0x19, 0xd9, 0x74, 0x8f, 0xff, 0x77, 0x79, 0xfd, 0xcf, 0x00, 0x57, 0xb6, 0x51, 0x37,
+	0xc8, 0x0c, 0x00, 0x00,
 }
@@ -21,6 +21,13 @@
 package rpc
 
 import (
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/yeeco/gyee/log"
 	"github.com/yeeco/gyee/rpc/pb"
 	"google.golang.org/grpc"
 )
@@ -30,25 +37,170 @@ import (
 //All the service function related to other YeeChain modules, using Yeelet to organize.
 
 type Server struct {
-	rpcServer *grpc.Server
+	node       Node
+	rpcServer  *grpc.Server // api service, on tcp
+	ipcServer  *grpc.Server // admin and api service, on ipc only
+	gateway    *httpGateway
+	httpServer *http.Server
+
+	lock          sync.Mutex
+	listeners     []net.Listener
+	ipcListener   net.Listener
+	httpListeners []net.Listener
+	wg            sync.WaitGroup
 }
 
-func NewServer() *Server {
-	rpc := grpc.NewServer()
-	srv := &Server{rpcServer: rpc}
-	admin := &AdminService{server: srv}
-	api := &APIService{server: srv}
-	rpcpb.RegisterAdminServiceServer(rpc, admin)
+func NewServer(node Node) *Server {
+	rpc, ipc := grpc.NewServer(), grpc.NewServer()
+	srv := &Server{node: node, rpcServer: rpc, ipcServer: ipc}
+	admin := &AdminService{server: srv, node: node}
+	api := &APIService{server: srv, node: node}
 	rpcpb.RegisterApiServiceServer(rpc, api)
+	rpcpb.RegisterAdminServiceServer(ipc, admin)
+	rpcpb.RegisterApiServiceServer(ipc, api)
+	conf := node.Config().Rpc
+	if conf.HttpAdmin {
+		srv.gateway = newHTTPGateway(admin, api, conf.HttpCors)
+	} else {
+		srv.gateway = newHTTPGateway(nil, api, conf.HttpCors)
+	}
+	srv.httpServer = &http.Server{Handler: srv.gateway}
 
 	return srv
 }
 
+// Start serves grpc on each rpc_listen tcp address, and on ipc_path unix socket
+//   and http json gateway on each http_listen address
+//   admin service is served on ipc only
 func (s *Server) Start() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	log.Info("RPC Server Start...")
 
+	conf := s.node.Config()
+	for _, addr := range conf.Rpc.RpcListen {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			s.closeListeners()
+			return err
+		}
+		s.listeners = append(s.listeners, listener)
+	}
+	if endpoint := conf.IPCEndpoint(); endpoint != "" {
+		listener, err := listenIPC(endpoint)
+		if err != nil {
+			s.closeListeners()
+			return err
+		}
+		s.ipcListener = listener
+	}
+	for _, addr := range conf.Rpc.HttpListen {
+		listener, err := net.Listen("tcp", addr)
+		if err != nil {
+			s.closeListeners()
+			return err
+		}
+		s.httpListeners = append(s.httpListeners, listener)
+	}
+
+	for _, listener := range s.listeners {
+		s.serveRPC(s.rpcServer, listener)
+	}
+	if s.ipcListener != nil {
+		s.serveRPC(s.ipcServer, s.ipcListener)
+	}
+	for _, listener := range s.httpListeners {
+		log.Info("HTTP gateway listening", "addr", listener.Addr())
+		s.wg.Add(1)
+		go func(listener net.Listener) {
+			defer s.wg.Done()
+			if err := s.httpServer.Serve(listener); err != http.ErrServerClosed {
+				log.Error("HTTP gateway serve", "addr", listener.Addr(), "err", err)
+			}
+		}(listener)
+	}
 	return nil
 }
 
 func (s *Server) Stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	log.Info("RPC Server Stop...")
+
+	// closes listeners and connections
+	s.rpcServer.Stop()
+	s.ipcServer.Stop()
+	s.httpServer.Close()
+	s.gateway.close()
+	s.wg.Wait()
+	s.listeners = nil
+	s.ipcListener = nil
+	s.httpListeners = nil
+}
+
+func (s *Server) serveRPC(server *grpc.Server, listener net.Listener) {
+	log.Info("RPC Server listening", "addr", listener.Addr())
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := server.Serve(listener); err != nil {
+			log.Error("RPC Server serve", "addr", listener.Addr(), "err", err)
+		}
+	}()
+}
+
+// grpc addresses served, tcp listeners first
+func (s *Server) Addrs() []net.Addr {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	addrs := listenerAddrs(s.listeners)
+	if s.ipcListener != nil {
+		addrs = append(addrs, s.ipcListener.Addr())
+	}
+	return addrs
+}
+
+// http gateway addresses served
+func (s *Server) HTTPAddrs() []net.Addr {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return listenerAddrs(s.httpListeners)
+}
+
+func listenerAddrs(listeners []net.Listener) []net.Addr {
+	addrs := make([]net.Addr, 0, len(listeners))
+	for _, listener := range listeners {
+		addrs = append(addrs, listener.Addr())
+	}
+	return addrs
+}
+
+func (s *Server) closeListeners() {
+	for _, listener := range append(s.listeners, s.httpListeners...) {
+		listener.Close()
+	}
+	if s.ipcListener != nil {
+		s.ipcListener.Close()
+	}
+	s.listeners = nil
+	s.ipcListener = nil
+	s.httpListeners = nil
+}
 
+// unix socket accessible by node owner only
+func listenIPC(endpoint string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(endpoint), 0751); err != nil {
+		return nil, err
+	}
+	// remove socket file left by last run
+	os.Remove(endpoint)
+	listener, err := net.Listen("unix", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(endpoint, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
 }
@@ -0,0 +1,97 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/yeeco/gyee/rpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestServerListeners(t *testing.T) {
+	n := newTestNode(t)
+	defer n.close()
+	n.config.Rpc.RpcListen = []string{"127.0.0.1:0"}
+	n.config.Rpc.IpcPath = "gyee.ipc"
+	n.config.Rpc.HttpListen = []string{"127.0.0.1:0"}
+
+	srv := NewServer(n)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() %v", err)
+	}
+	defer srv.Stop()
+	addrs := srv.Addrs()
+	if len(addrs) != 2 || addrs[0].Network() != "tcp" || addrs[1].String() != n.config.IPCEndpoint() {
+		t.Fatalf("Addrs() got %v", addrs)
+	}
+	ctx := context.Background()
+
+	tcpConn, err := grpc.Dial(addrs[0].String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Dial() tcp %v", err)
+	}
+	defer tcpConn.Close()
+	if resp, err := rpcpb.NewApiServiceClient(tcpConn).NodeInfo(ctx, &rpcpb.NonParamsRequest{}); err != nil || resp.Id != n.NodeID() {
+		t.Errorf("NodeInfo() on tcp %v %v", resp, err)
+	}
+	// admin service on ipc only
+	_, err = rpcpb.NewAdminServiceClient(tcpConn).NewAccount(ctx, &rpcpb.NewAccountRequest{Passphrase: "secret"})
+	expectCode(t, "NewAccount() on tcp", err, codes.Unimplemented)
+
+	ipcConn, err := DialIPC(n.config.IPCEndpoint())
+	if err != nil {
+		t.Fatalf("DialIPC() %v", err)
+	}
+	defer ipcConn.Close()
+	if resp, err := rpcpb.NewApiServiceClient(ipcConn).CurrentHeight(ctx, &rpcpb.NonParamsRequest{}); err != nil || resp.Height != 0 {
+		t.Errorf("CurrentHeight() on ipc %v %v", resp, err)
+	}
+	account, err := rpcpb.NewAdminServiceClient(ipcConn).NewAccount(ctx, &rpcpb.NewAccountRequest{Passphrase: "secret"})
+	if err != nil {
+		t.Fatalf("NewAccount() on ipc %v", err)
+	}
+	if accounts := n.accounts.Accounts(); len(accounts) != 1 || accounts[0].String() != account.Address {
+		t.Errorf("NewAccount() %v, accounts %v", account, accounts)
+	}
+
+	httpAddrs := srv.HTTPAddrs()
+	if len(httpAddrs) != 1 {
+		t.Fatalf("HTTPAddrs() got %v", httpAddrs)
+	}
+	httpResp, err := http.Post("http://"+httpAddrs[0].String()+"/v1/user/nodeinfo", "application/json", nil)
+	if err != nil {
+		t.Fatalf("http nodeinfo %v", err)
+	}
+	body, _ := ioutil.ReadAll(httpResp.Body)
+	httpResp.Body.Close()
+	if httpResp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"id":"test"`) {
+		t.Errorf("http nodeinfo got %d %s", httpResp.StatusCode, body)
+	}
+
+	srv.Stop()
+	if _, err := os.Stat(n.config.IPCEndpoint()); !os.IsNotExist(err) {
+		t.Errorf("ipc socket left after Stop(), %v", err)
+	}
+}
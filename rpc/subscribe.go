@@ -0,0 +1,97 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/core"
+	"github.com/yeeco/gyee/rpc/pb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// events buffered for a subscriber not keeping up, before it is dropped
+const SubscriptionBuffer = 256
+
+// topics of websocket subscriptions
+var subscriptionTopics = map[string]core.EventType{
+	"newHeads":   core.EventNewHead,
+	"pendingTxs": core.EventNewPendingTx,
+	"sealedTxs":  core.EventSealedTxs,
+}
+
+func (s *APIService) SubscribeNewHeads(req *rpcpb.NonParamsRequest, stream rpcpb.ApiService_SubscribeNewHeadsServer) error {
+	return s.subscribe(stream.Context(), core.EventNewHead, func(msg proto.Message) error {
+		return stream.Send(msg.(*rpcpb.BlockResponse))
+	})
+}
+
+func (s *APIService) SubscribePendingTxs(req *rpcpb.NonParamsRequest, stream rpcpb.ApiService_SubscribePendingTxsServer) error {
+	return s.subscribe(stream.Context(), core.EventNewPendingTx, func(msg proto.Message) error {
+		return stream.Send(msg.(*rpcpb.Transaction))
+	})
+}
+
+func (s *APIService) SubscribeSealedTxs(req *rpcpb.NonParamsRequest, stream rpcpb.ApiService_SubscribeSealedTxsServer) error {
+	return s.subscribe(stream.Context(), core.EventSealedTxs, func(msg proto.Message) error {
+		return stream.Send(msg.(*rpcpb.SealedTxsResponse))
+	})
+}
+
+// send events of type until ctx done, or subscriber dropped for falling behind
+func (s *APIService) subscribe(ctx context.Context, t core.EventType, send func(proto.Message) error) error {
+	sub := s.node.Core().EventFeed().Subscribe(SubscriptionBuffer, t)
+	defer sub.Unsubscribe()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev, ok := <-sub.Chan():
+			if !ok {
+				return status.Error(codes.ResourceExhausted, sub.Err().Error())
+			}
+			if err := send(eventMessage(ev)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func eventMessage(ev *core.Event) proto.Message {
+	switch ev.Type {
+	case core.EventNewHead:
+		return blockResponse(ev.Block)
+	case core.EventNewPendingTx:
+		return transactionMessage(ev.Tx)
+	case core.EventSealedTxs:
+		msg := &rpcpb.SealedTxsResponse{
+			Height:    ev.Sealed.Height,
+			Timestamp: uint64(ev.Sealed.Time.UnixNano() / int64(time.Millisecond)),
+			TxHashes:  make([]string, 0, len(ev.Sealed.Txs)),
+		}
+		for _, hash := range ev.Sealed.Txs {
+			msg.TxHashes = append(msg.TxHashes, hash.Hex())
+		}
+		return msg
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright (C) 2019 gyee authors
+//
+// This file is part of the gyee library.
+//
+// The gyee library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The gyee library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with the gyee library.  If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/yeeco/gyee/common"
+	"github.com/yeeco/gyee/core"
+	"github.com/yeeco/gyee/rpc/pb"
+	"golang.org/x/net/websocket"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// wait for count subscribers in feed
+func waitSubscribers(t *testing.T, feed *core.EventFeed, count int) {
+	for i := 0; feed.Len() != count; i++ {
+		if i > 100 {
+			t.Fatalf("feed got %d subscribers, need %d", feed.Len(), count)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestSubscribeStreams(t *testing.T) {
+	n := newTestNode(t)
+	defer n.close()
+	n.config.Rpc.RpcListen = []string{"127.0.0.1:0"}
+	srv := NewServer(n)
+	if err := srv.Start(); err != nil {
+		t.Fatalf("Start() %v", err)
+	}
+	defer srv.Stop()
+	conn, err := grpc.Dial(srv.Addrs()[0].String(), grpc.WithInsecure())
+	if err != nil {
+		t.Fatalf("Dial() %v", err)
+	}
+	defer conn.Close()
+	client := rpcpb.NewApiServiceClient(conn)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	heads, err := client.SubscribeNewHeads(ctx, &rpcpb.NonParamsRequest{})
+	if err != nil {
+		t.Fatalf("SubscribeNewHeads() %v", err)
+	}
+	pending, err := client.SubscribePendingTxs(ctx, &rpcpb.NonParamsRequest{})
+	if err != nil {
+		t.Fatalf("SubscribePendingTxs() %v", err)
+	}
+	sealed, err := client.SubscribeSealedTxs(ctx, &rpcpb.NonParamsRequest{})
+	if err != nil {
+		t.Fatalf("SubscribeSealedTxs() %v", err)
+	}
+	feed := n.core.EventFeed()
+	waitSubscribers(t, feed, 3)
+
+	_, recipient := newTestKey(t)
+	tx := n.newTx(t, 0, recipient, 100)
+	if err := n.core.TxPool().SubmitTx(tx); err != nil {
+		t.Fatalf("SubmitTx() %v", err)
+	}
+	if msg, err := pending.Recv(); err != nil || msg.Hash != tx.Hash().Hex() {
+		t.Errorf("pending tx got %v %v", msg, err)
+	}
+	feed.Send(&core.Event{Type: core.EventSealedTxs, Sealed: &core.SealedTxs{
+		Height: 1, Time: time.Unix(10, 0), Txs: []common.Hash{*tx.Hash()},
+	}})
+	if msg, err := sealed.Recv(); err != nil || msg.Height != 1 || msg.Timestamp != 10000 ||
+		len(msg.TxHashes) != 1 || msg.TxHashes[0] != tx.Hash().Hex() {
+		t.Errorf("sealed txs got %v %v", msg, err)
+	}
+	b := n.addBlock(t, tx)
+	if msg, err := heads.Recv(); err != nil || msg.Hash != b.Hash().Hex() || len(msg.Transactions) != 1 {
+		t.Errorf("new head got %v %v", msg, err)
+	}
+
+	// subscriptions end with server
+	srv.Stop()
+	if _, err := heads.Recv(); err == nil {
+		t.Errorf("stream not ended by Stop()")
+	}
+}
+
+func TestSubscribeDropSlow(t *testing.T) {
+	n := newTestNode(t)
+	defer n.close()
+	api := &APIService{node: n}
+	feed := n.core.EventFeed()
+
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- api.subscribe(context.Background(), core.EventNewHead, func(msg proto.Message) error {
+			<-release
+			return nil
+		})
+	}()
+	waitSubscribers(t, feed, 1)
+	head := n.core.Chain().LastBlock()
+	for i := 0; i < SubscriptionBuffer+2; i++ {
+		feed.Send(&core.Event{Type: core.EventNewHead, Block: head})
+	}
+	close(release)
+	if err := <-done; status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("slow subscriber got %v", err)
+	}
+	if feed.Len() != 0 {
+		t.Errorf("feed got %d subscribers", feed.Len())
+	}
+}
+
+func TestSubscribeWebSocket(t *testing.T) {
+	n := newTestNode(t)
+	defer n.close()
+	g := newHTTPGateway(&AdminService{node: n}, &APIService{node: n}, nil)
+	hs := httptest.NewServer(g)
+	defer hs.Close()
+	defer g.close()
+	wsURL := "ws" + strings.TrimPrefix(hs.URL, "http") + "/v1/subscribe?topic="
+
+	if _, err := websocket.Dial(wsURL+"unknown", "", hs.URL); err == nil {
+		t.Errorf("unknown topic subscribed")
+	}
+	if _, err := websocket.Dial(wsURL+"newHeads", "", "http://other.local"); err == nil {
+		t.Errorf("subscribed from disallowed origin")
+	}
+
+	ws, err := websocket.Dial(wsURL+"newHeads", "", hs.URL)
+	if err != nil {
+		t.Fatalf("websocket.Dial() %v", err)
+	}
+	defer ws.Close()
+	waitSubscribers(t, n.core.EventFeed(), 1)
+
+	b := n.addBlock(t)
+	var msg struct {
+		Result *struct {
+			Hash   string `json:"hash"`
+			Header struct {
+				Number string `json:"number"`
+			} `json:"header"`
+		} `json:"result"`
+	}
+	ws.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if err := websocket.JSON.Receive(ws, &msg); err != nil {
+		t.Fatalf("receive %v", err)
+	}
+	if msg.Result == nil || msg.Result.Hash != b.Hash().Hex() || msg.Result.Header.Number != "1" {
+		t.Errorf("new head got %+v", msg.Result)
+	}
+
+	// subscription ends when client leaves
+	ws.Close()
+	waitSubscribers(t, n.core.EventFeed(), 0)
+}
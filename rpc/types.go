@@ -20,7 +20,21 @@
 
 package rpc
 
+import (
+	"github.com/yeeco/gyee/accounts"
+	"github.com/yeeco/gyee/config"
+	"github.com/yeeco/gyee/core"
+)
+
 type RPCServer interface {
 	Start() error
 	Stop()
 }
+
+// Node is the running node served by rpc services
+type Node interface {
+	NodeID() string
+	Config() *config.Config
+	AccountManager() *accounts.AccountManager
+	Core() *core.Core
+}
@@ -82,6 +82,13 @@ Exit:
 			case <-ticker.C:
 				// send txs
 			}
+			// keep txs of sender in pool under account cap, rejections are noise
+			if st, err := fn.Core().Chain().State(); err == nil {
+				if account := st.GetAccount(addrs[i], false); account != nil &&
+					nonces[i]+uint64(numNodes) > account.Nonce()+core.MaxAccountTxs {
+					continue
+				}
+			}
 			for j, tn := range nodes {
 				if j == i {
 					continue